@@ -10,6 +10,8 @@ import (
 	"sort"
 	"sync"
 
+	"kratos_c/log"
+
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -2,20 +2,34 @@ package main
 
 import (
 	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
 
 	"google.golang.org/genproto/googleapis/api/annotations"
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 const release = "v2.9.2"
 
 const (
 	contextPackage       = protogen.GoImportPath("context")
-	transportHTTPPackage = protogen.GoImportPath("github.com/go-kratos/kratos/v2/transport/http")
-	bindingPackage       = protogen.GoImportPath("github.com/go-kratos/kratos/v2/transport/http/binding")
+	transportHTTPPackage = protogen.GoImportPath("kratos_c/transport/http")
+	bindingPackage       = protogen.GoImportPath("kratos_c/transport/http/binding")
 )
 
+const deprecationComment = "// Deprecated: Do not use."
+
+// 同名方法可能有多个 http 绑定, 用于生成不重复的 handler 名称
+var methodSets = make(map[string]int)
+
+// pathVarRe 匹配路径变量, 例如 {id}, {user.id}, {name=shelves/*}
+var pathVarRe = regexp.MustCompile(`(?i){([a-z.0-9_\s]*)=?([^{}]*)}`)
+
 func hasHTTPRule(services []*protogen.Service) bool {
 	for _, service := range services {
 		for _, method := range service.Methods {
@@ -51,6 +65,7 @@ func generateFile(gen *protogen.Plugin, file *protogen.File, omitempty bool, omi
 	g.P()
 	g.P("package ", file.GoPackageName)
 	g.P()
+	generateFileContent(gen, file, g, omitempty, omitemptyPrefix)
 	return g
 }
 
@@ -65,8 +80,187 @@ func generateFileContent(gen *protogen.Plugin, file *protogen.File, g *protogen.
 	g.P("var _ = ", bindingPackage.Ident("EncodeURL"))
 	g.P("const _ = ", transportHTTPPackage.Ident("SupportPackageIsVersion1"))
 	g.P()
-	for range file.Services {
-		// todo
+	for _, service := range file.Services {
+		genService(gen, file, g, service, omitempty, omitemptyPrefix)
+	}
+}
+
+func genService(_ *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, service *protogen.Service, omitempty bool, omitemptyPrefix string) {
+	if service.Desc.Options().(*descriptorpb.ServiceOptions).GetDeprecated() {
+		g.P("//")
+		g.P(deprecationComment)
+	}
+	sd := &serviceDesc{
+		ServiceType: service.GoName,
+		ServiceName: string(service.Desc.FullName()),
+		Metadata:    file.Desc.Path(),
+	}
+	for _, method := range service.Methods {
+		if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
+			continue
+		}
+		rule, ok := proto.GetExtension(method.Desc.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if rule != nil && ok {
+			for _, bind := range rule.AdditionalBindings {
+				sd.Methods = append(sd.Methods, buildHTTPRule(g, method, bind))
+			}
+			sd.Methods = append(sd.Methods, buildHTTPRule(g, method, rule))
+		} else if !omitempty {
+			// 没有 http 规则时默认使用 POST /{prefix}/{package.Service}/{Method}
+			path := fmt.Sprintf("%s/%s/%s", omitemptyPrefix, service.Desc.FullName(), method.Desc.Name())
+			md := buildMethodDesc(g, method, http.MethodPost, path)
+			md.HasBody = true
+			sd.Methods = append(sd.Methods, md)
+		}
+	}
+	if len(sd.Methods) != 0 {
+		g.P(sd.execute())
+	}
+}
+
+func buildHTTPRule(g *protogen.GeneratedFile, m *protogen.Method, rule *annotations.HttpRule) *methodDesc {
+	var (
+		path         string
+		method       string
+		body         = rule.Body
+		responseBody = rule.ResponseBody
+	)
+
+	switch pattern := rule.Pattern.(type) {
+	case *annotations.HttpRule_Get:
+		path = pattern.Get
+		method = http.MethodGet
+	case *annotations.HttpRule_Put:
+		path = pattern.Put
+		method = http.MethodPut
+	case *annotations.HttpRule_Post:
+		path = pattern.Post
+		method = http.MethodPost
+	case *annotations.HttpRule_Delete:
+		path = pattern.Delete
+		method = http.MethodDelete
+	case *annotations.HttpRule_Patch:
+		path = pattern.Patch
+		method = http.MethodPatch
+	case *annotations.HttpRule_Custom:
+		path = pattern.Custom.Path
+		method = pattern.Custom.Kind
+	}
+	md := buildMethodDesc(g, m, method, path)
+	if method == http.MethodGet || method == http.MethodDelete {
+		if body != "" {
+			_, _ = fmt.Fprintf(os.Stderr, "\u001B[31mWARN\u001B[m: %s %s body should not be declared.\n", method, path)
+		}
+	} else {
+		if body == "" {
+			_, _ = fmt.Fprintf(os.Stderr, "\u001B[31mWARN\u001B[m: %s %s does not declare a body.\n", method, path)
+		}
+	}
+	if body == "*" {
+		md.HasBody = true
+		md.Body = ""
+	} else if body != "" {
+		md.HasBody = true
+		md.Body = "." + goFieldPath(m.Input, body, path)
+	} else {
+		md.HasBody = false
+	}
+	if responseBody == "*" {
+		md.ResponseBody = ""
+	} else if responseBody != "" {
+		md.ResponseBody = "." + goFieldPath(m.Output, responseBody, path)
+	}
+	return md
+}
+
+func buildMethodDesc(g *protogen.GeneratedFile, m *protogen.Method, method, path string) *methodDesc {
+	defer func() { methodSets[m.GoName]++ }()
+
+	vars := buildPathVars(path)
+	for _, v := range vars {
+		// 校验路径变量对应的字段存在且为标量
+		fields := m.Input.Desc.Fields()
+		for _, field := range strings.Split(v, ".") {
+			if strings.TrimSpace(field) == "" {
+				continue
+			}
+			fd := fields.ByName(protoreflect.Name(field))
+			if fd == nil {
+				_, _ = fmt.Fprintf(os.Stderr, "\u001B[31mERROR\u001B[m: The corresponding field '%s' declaration in message could not be found in '%s'\n", v, path)
+				os.Exit(2)
+			}
+			if fd.IsMap() {
+				_, _ = fmt.Fprintf(os.Stderr, "\u001B[31mWARN\u001B[m: The field in path:'%s' shouldn't be a map.\n", v)
+			} else if fd.IsList() {
+				_, _ = fmt.Fprintf(os.Stderr, "\u001B[31mWARN\u001B[m: The field in path:'%s' shouldn't be a list.\n", v)
+			} else if fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+				fields = fd.Message().Fields()
+			}
+		}
+	}
+	comment := m.Comments.Leading.String() + m.Comments.Trailing.String()
+	if comment != "" {
+		comment = "// " + m.GoName + strings.TrimPrefix(strings.TrimSuffix(comment, "\n"), "//")
+	}
+	return &methodDesc{
+		Name:         m.GoName,
+		OriginalName: string(m.Desc.Name()),
+		Num:          methodSets[m.GoName],
+		Request:      g.QualifiedGoIdent(m.Input.GoIdent),
+		Reply:        g.QualifiedGoIdent(m.Output.GoIdent),
+		Comment:      comment,
+		Path:         path,
+		Method:       method,
+		HasVars:      len(vars) > 0,
+	}
+}
+
+func buildPathVars(path string) (res []string) {
+	// 自定义方法只能出现在末尾, 例如 /v1/{name}:cancel, 校验路径前先去掉
+	path = strings.TrimSuffix(path, pathVerb(path))
+	if strings.ContainsRune(pathVarRe.ReplaceAllString(path, ""), ':') {
+		_, _ = fmt.Fprintf(os.Stderr, "\u001B[31mWARN\u001B[m: Path %s custom verb should be at the end \n", path)
+	}
+	if strings.HasSuffix(path, "/") {
+		_, _ = fmt.Fprintf(os.Stderr, "\u001B[31mWARN\u001B[m: Path %s should not end with \"/\" \n", path)
+	}
+	for _, m := range pathVarRe.FindAllStringSubmatch(path, -1) {
+		res = append(res, strings.TrimSpace(m[1]))
 	}
 	return
 }
+
+// pathVerb 返回路径末尾的自定义方法, 例如 /v1/{name}:cancel 返回 :cancel
+func pathVerb(path string) string {
+	i := strings.LastIndexByte(path, ':')
+	if i < 0 || i < strings.LastIndexAny(path, "/}") {
+		return ""
+	}
+	return path[i:]
+}
+
+// goFieldPath 将 body/response_body 中的字段路径(例如 user.profile)转换为 Go 字段路径(User.Profile)
+func goFieldPath(msg *protogen.Message, fieldPath, path string) string {
+	names := strings.Split(fieldPath, ".")
+	goNames := make([]string, 0, len(names))
+	for i, name := range names {
+		var field *protogen.Field
+		if msg != nil {
+			for _, f := range msg.Fields {
+				if string(f.Desc.Name()) == name {
+					field = f
+					break
+				}
+			}
+		}
+		if field == nil {
+			_, _ = fmt.Fprintf(os.Stderr, "\u001B[31mERROR\u001B[m: The corresponding field '%s' declaration in message could not be found in '%s'\n", fieldPath, path)
+			os.Exit(2)
+		}
+		goNames = append(goNames, field.GoName)
+		if i < len(names)-1 {
+			msg = field.Message
+		}
+	}
+	return strings.Join(goNames, ".")
+}
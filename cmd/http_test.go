@@ -0,0 +1,29 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildPathVars(t *testing.T) {
+	tests := []struct {
+		path string
+		verb string
+		vars []string
+	}{
+		{"/v1/users/{id}", "", []string{"id"}},
+		{"/v1/{user.id}/books/{book_id}", "", []string{"user.id", "book_id"}},
+		{"/v1/{name=shelves/*}", "", []string{"name"}},
+		{"/v1/{name}:cancel", ":cancel", []string{"name"}},
+		{"/v1/{name=operations/*}:cancel", ":cancel", []string{"name"}},
+		{"/v1/books:batchGet", ":batchGet", nil},
+	}
+	for _, tt := range tests {
+		if verb := pathVerb(tt.path); verb != tt.verb {
+			t.Errorf("pathVerb(%q) = %q, want %q", tt.path, verb, tt.verb)
+		}
+		if vars := buildPathVars(tt.path); !reflect.DeepEqual(vars, tt.vars) {
+			t.Errorf("buildPathVars(%q) = %v, want %v", tt.path, vars, tt.vars)
+		}
+	}
+}
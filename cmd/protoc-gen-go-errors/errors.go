@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"kratos_c/errors"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const release = "v2.9.2"
+
+const (
+	errorsPackage = protogen.GoImportPath("kratos_c/errors")
+	fmtPackage    = protogen.GoImportPath("fmt")
+)
+
+// 超出 [0, 600] 的状态码不是合法的 HTTP 状态码
+const maxCode = 600
+
+// generateFile generates a _errors.pb.go file containing kratos errors definitions.
+func generateFile(gen *protogen.Plugin, file *protogen.File) *protogen.GeneratedFile {
+	if len(file.Enums) == 0 {
+		return nil
+	}
+	filename := file.GeneratedFilenamePrefix + "_errors.pb.go"
+	g := gen.NewGeneratedFile(filename, file.GoImportPath)
+	g.P("// Code generated by protoc-gen-go-errors. DO NOT EDIT.")
+	g.P("// versions:")
+	g.P(fmt.Sprintf("// -protoc-gen-go-errors %s", release))
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("package ", file.GoPackageName)
+	g.P()
+	// 模板中直接使用 fmt 与 errors, 这里先登记导入
+	g.QualifiedGoIdent(fmtPackage.Ident(""))
+	g.QualifiedGoIdent(errorsPackage.Ident(""))
+	generateFileContent(gen, file, g)
+	return g
+}
+
+// generateFileContent generates the kratos errors definitions, excluding the package statement.
+func generateFileContent(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile) {
+	g.P("// This is a compile-time assertion to ensure that this generated file")
+	g.P("// is compatible with the kratos package it is being compiled against.")
+	g.P("const _ = ", errorsPackage.Ident("SupportPackageIsVersion1"))
+	g.P()
+	generated := false
+	names := reasonNames(gen, file)
+	for _, enum := range allEnums(file) {
+		if genErrorsReason(names, g, enum) {
+			generated = true
+		}
+	}
+	// 所有枚举都没有声明错误码时不生成文件
+	if !generated {
+		g.Skip()
+	}
+}
+
+// allEnums 返回文件中定义的全部枚举, 包括嵌套在消息中的枚举
+func allEnums(file *protogen.File) []*protogen.Enum {
+	enums := append([]*protogen.Enum(nil), file.Enums...)
+	var walk func(msgs []*protogen.Message)
+	walk = func(msgs []*protogen.Message) {
+		for _, m := range msgs {
+			enums = append(enums, m.Enums...)
+			walk(m.Messages)
+		}
+	}
+	walk(file.Messages)
+	return enums
+}
+
+// reasonNames 计算同一个 Go 包内每个错误枚举值生成的函数名 (去掉 Is/Error 前缀).
+// 不同枚举中同名的值 (例如嵌套在不同消息中的 NOT_FOUND) 会加上枚举名前缀, 避免生成重复的函数.
+func reasonNames(gen *protogen.Plugin, file *protogen.File) map[protoreflect.FullName]string {
+	var values []*protogen.EnumValue
+	count := make(map[string]int)
+	for _, f := range gen.Files {
+		if !f.Generate || f.GoImportPath != file.GoImportPath {
+			continue
+		}
+		for _, enum := range allEnums(f) {
+			defaultCode := int(proto.GetExtension(enum.Desc.Options(), errors.E_DefaultCode).(int32))
+			for _, v := range enum.Values {
+				if valueCode(defaultCode, v) == 0 {
+					continue
+				}
+				count[case2Camel(string(v.Desc.Name()))]++
+				values = append(values, v)
+			}
+		}
+	}
+	names := make(map[protoreflect.FullName]string, len(values))
+	seen := make(map[string]protoreflect.FullName, len(values))
+	for _, v := range values {
+		name := case2Camel(string(v.Desc.Name()))
+		if count[name] > 1 {
+			name = case2Camel(v.Parent.GoIdent.GoName) + name
+		}
+		if other, ok := seen[name]; ok {
+			_, _ = fmt.Fprintf(os.Stderr, "\u001B[31mERROR\u001B[m: Enum values '%s' and '%s' both generate Is%s\n", other, v.Desc.FullName(), name)
+			os.Exit(2)
+		}
+		seen[name] = v.Desc.FullName()
+		names[v.Desc.FullName()] = name
+	}
+	return names
+}
+
+// valueCode 返回枚举值的错误码, 没有单独声明 code 时使用枚举的 default_code
+func valueCode(defaultCode int, v *protogen.EnumValue) int {
+	if code := int(proto.GetExtension(v.Desc.Options(), errors.E_Code).(int32)); code != 0 {
+		return code
+	}
+	return defaultCode
+}
+
+// genErrorsReason 为枚举中每个有错误码的值生成 IsXxx/ErrorXxx, 没有生成任何内容时返回 false.
+func genErrorsReason(names map[protoreflect.FullName]string, g *protogen.GeneratedFile, enum *protogen.Enum) bool {
+	defaultCode := int(proto.GetExtension(enum.Desc.Options(), errors.E_DefaultCode).(int32))
+	if defaultCode > maxCode || defaultCode < 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "\u001B[31mERROR\u001B[m: Enum '%s' default_code %d must be in the range [0, %d]\n", enum.Desc.FullName(), defaultCode, maxCode)
+		os.Exit(2)
+	}
+	var ew errorWrapper
+	for _, v := range enum.Values {
+		enumCode := valueCode(defaultCode, v)
+		if enumCode > maxCode || enumCode < 0 {
+			_, _ = fmt.Fprintf(os.Stderr, "\u001B[31mERROR\u001B[m: Enum value '%s' code %d must be in the range [0, %d]\n", v.Desc.FullName(), enumCode, maxCode)
+			os.Exit(2)
+		}
+		// 没有 default_code 也没有 code 的枚举值不是错误原因
+		if enumCode == 0 {
+			continue
+		}
+		comment := v.Comments.Leading.String()
+		if comment == "" {
+			comment = v.Comments.Trailing.String()
+		}
+		ew.Errors = append(ew.Errors, &errorInfo{
+			Value:      g.QualifiedGoIdent(v.GoIdent),
+			CamelValue: names[v.Desc.FullName()],
+			HTTPCode:   enumCode,
+			Comment:    comment,
+			HasComment: len(comment) > 0,
+		})
+	}
+	if len(ew.Errors) == 0 {
+		return false
+	}
+	g.P(ew.execute())
+	// 多个枚举之间空一行
+	g.P()
+	return true
+}
+
+// case2Camel 将 USER_NOT_FOUND 或 user_not_found 转换为 UserNotFound
+func case2Camel(name string) string {
+	if !strings.Contains(name, "_") {
+		if name == strings.ToUpper(name) {
+			name = strings.ToLower(name)
+		}
+		return upperFirst(name)
+	}
+	parts := strings.Split(strings.ToLower(name), "_")
+	for i, part := range parts {
+		parts[i] = upperFirst(part)
+	}
+	return strings.Join(parts, "")
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
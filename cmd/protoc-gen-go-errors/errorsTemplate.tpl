@@ -0,0 +1,17 @@
+{{ range .Errors }}
+
+{{ if .HasComment }}{{ .Comment }}{{ end -}}
+func Is{{ .CamelValue }}(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == {{ .Value }}.String() && e.Code == {{ .HTTPCode }}
+}
+
+{{ if .HasComment }}{{ .Comment }}{{ end -}}
+func Error{{ .CamelValue }}(format string, args ...any) *errors.Error {
+	return errors.New({{ .HTTPCode }}, {{ .Value }}.String(), fmt.Sprintf(format, args...))
+}
+
+{{- end }}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"kratos_c/errors"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// generate 使用 testdata 中的描述符运行插件, 返回生成的文件
+func generate(t *testing.T, fixture string) []*pluginpb.CodeGeneratorResponse_File {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd := &descriptorpb.FileDescriptorProto{}
+	if err = prototext.Unmarshal(data, fd); err != nil {
+		t.Fatal(err)
+	}
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{fd.GetName()},
+		Parameter:      proto.String("paths=source_relative"),
+		ProtoFile: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto),
+			protodesc.ToFileDescriptorProto(errors.File_errors_errors_proto),
+			fd,
+		},
+	}
+	gen, err := protogen.Options{}.New(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range gen.Files {
+		if f.Generate {
+			generateFile(gen, f)
+		}
+	}
+	resp := gen.Response()
+	if resp.Error != nil {
+		t.Fatal(resp.GetError())
+	}
+	return resp.GetFile()
+}
+
+func TestGenerateGolden(t *testing.T) {
+	files := generate(t, "error_reason.txtpb")
+	if len(files) != 1 {
+		t.Fatalf("got %d generated files, want 1", len(files))
+	}
+	f := files[0]
+	if got, want := f.GetName(), "error_reason_errors.pb.go"; got != want {
+		t.Errorf("got file name %q, want %q", got, want)
+	}
+	golden := filepath.Join("testdata", f.GetName()+".golden")
+	if *update {
+		if err := os.WriteFile(golden, []byte(f.GetContent()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.GetContent(); got != string(want) {
+		t.Errorf("generated code does not match %s, run go test -update to regenerate\n%s", golden, got)
+	}
+}
+
+func TestGenerateCollidingNames(t *testing.T) {
+	content := generate(t, "error_reason.txtpb")[0].GetContent()
+	for _, want := range []string{
+		"func IsErrorReasonUserNotFound(",
+		"func ErrorErrorReasonUserNotFound(",
+		"func IsOrderReasonUserNotFound(",
+		"func ErrorOrderReasonUserNotFound(",
+		"func IsContentMissing(",
+		"func IsOrderClosed(",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("generated code does not contain %q", want)
+		}
+	}
+	for _, unwanted := range []string{"func IsUserNotFound(", "PlainValue"} {
+		if strings.Contains(content, unwanted) {
+			t.Errorf("generated code contains %q", unwanted)
+		}
+	}
+}
+
+func TestCase2Camel(t *testing.T) {
+	tests := map[string]string{
+		"USER_NOT_FOUND": "UserNotFound",
+		"user_not_found": "UserNotFound",
+		"NOTFOUND":       "Notfound",
+		"notFound":       "NotFound",
+		"Order_Reason":   "OrderReason",
+		"":               "",
+	}
+	for in, want := range tests {
+		if got := case2Camel(in); got != want {
+			t.Errorf("case2Camel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
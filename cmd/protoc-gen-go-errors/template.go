@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"strings"
+	"text/template"
+)
+
+//go:embed errorsTemplate.tpl
+var errorsTemplate string
+
+type errorInfo struct {
+	Value      string // ErrorReason_USER_NOT_FOUND
+	CamelValue string // UserNotFound
+	HTTPCode   int
+	Comment    string
+	HasComment bool
+}
+
+type errorWrapper struct {
+	Errors []*errorInfo
+}
+
+func (e *errorWrapper) execute() string {
+	buf := new(bytes.Buffer)
+	tmpl, err := template.New("errors").Parse(strings.TrimSpace(errorsTemplate))
+	if err != nil {
+		panic(err)
+	}
+	if err := tmpl.Execute(buf, e); err != nil {
+		panic(err)
+	}
+	return strings.Trim(buf.String(), "\r\n")
+}
@@ -0,0 +1,228 @@
+package form
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"kratos_c/encoding"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// Name is form codec name
+	Name = "x-www-form-urlencoded"
+	// tagName 非 proto 结构体字段名使用的 tag, 未设置时回退到 json tag
+	tagName = "form"
+)
+
+func init() {
+	encoding.RegisterCodec(codec{})
+}
+
+// codec is a Codec implementation with application/x-www-form-urlencoded.
+// proto 消息按 grpc-gateway 的规则编解码, 其他类型支持 url.Values, map[string]string
+// 以及只包含标量字段的结构体.
+type codec struct{}
+
+func (codec) Marshal(v any) ([]byte, error) {
+	var (
+		vs  url.Values
+		err error
+	)
+	switch m := v.(type) {
+	case proto.Message:
+		vs, err = EncodeValues(m)
+	case url.Values:
+		vs = m
+	case map[string]string:
+		vs = make(url.Values, len(m))
+		for key, value := range m {
+			vs.Set(key, value)
+		}
+	default:
+		vs, err = encodeStruct(v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(vs.Encode()), nil
+}
+
+func (codec) Unmarshal(data []byte, v any) error {
+	vs, err := url.ParseQuery(string(data))
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("form: unmarshal target must be a non-nil pointer, got %T", v)
+	}
+	// 绑定到消息类型的子字段时 v 是 **T, 需要先分配内存
+	for rv.Elem().Kind() == reflect.Pointer {
+		if rv.Elem().IsNil() {
+			rv.Elem().Set(reflect.New(rv.Elem().Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+	switch m := rv.Interface().(type) {
+	case proto.Message:
+		return DecodeValues(m, vs)
+	case *url.Values:
+		*m = vs
+		return nil
+	case *map[string]string:
+		if *m == nil {
+			*m = make(map[string]string, len(vs))
+		}
+		for key := range vs {
+			(*m)[key] = vs.Get(key)
+		}
+		return nil
+	}
+	return decodeStruct(rv.Elem(), vs)
+}
+
+func (codec) Name() string {
+	return Name
+}
+
+func fieldName(f reflect.StructField) string {
+	tag := f.Tag.Get(tagName)
+	if tag == "" {
+		tag = f.Tag.Get("json")
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+func encodeStruct(v any) (url.Values, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("form: unsupported type %T", v)
+	}
+	vs := make(url.Values)
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		name := fieldName(f)
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 {
+			for j := 0; j < fv.Len(); j++ {
+				s, err := formatScalar(fv.Index(j))
+				if err != nil {
+					return nil, fmt.Errorf("form: field %s: %w", f.Name, err)
+				}
+				vs.Add(name, s)
+			}
+			continue
+		}
+		if fv.IsZero() {
+			continue
+		}
+		s, err := formatScalar(reflect.Indirect(fv))
+		if err != nil {
+			return nil, fmt.Errorf("form: field %s: %w", f.Name, err)
+		}
+		vs.Set(name, s)
+	}
+	return vs, nil
+}
+
+func decodeStruct(rv reflect.Value, vs url.Values) error {
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("form: unsupported type %s", rv.Type())
+	}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		name := fieldName(f)
+		values, ok := vs[name]
+		if !f.IsExported() || name == "-" || !ok {
+			continue
+		}
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 {
+			list := reflect.MakeSlice(fv.Type(), len(values), len(values))
+			for j, value := range values {
+				if err := parseScalar(list.Index(j), value); err != nil {
+					return fmt.Errorf("form: field %s: %w", f.Name, err)
+				}
+			}
+			fv.Set(list)
+			continue
+		}
+		if fv.Kind() == reflect.Pointer {
+			fv.Set(reflect.New(fv.Type().Elem()))
+			fv = fv.Elem()
+		}
+		if err := parseScalar(fv, values[0]); err != nil {
+			return fmt.Errorf("form: field %s: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+func formatScalar(v reflect.Value) (string, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.Slice:
+		return string(v.Bytes()), nil
+	default:
+		return "", fmt.Errorf("unsupported kind %s", v.Kind())
+	}
+}
+
+func parseScalar(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		v.SetBytes([]byte(s))
+	default:
+		return fmt.Errorf("unsupported kind %s", v.Kind())
+	}
+	return nil
+}
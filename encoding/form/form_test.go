@@ -0,0 +1,108 @@
+package form
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"kratos_c/encoding"
+	"kratos_c/internal/testdata/complex"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type loginRequest struct {
+	Username string   `form:"username"`
+	Password string   `json:"password,omitempty"`
+	Remember bool     `form:"remember"`
+	Age      *int     `form:"age"`
+	Scopes   []string `form:"scope"`
+	Ignored  string   `form:"-"`
+	Default  float64
+	private  string
+}
+
+func TestCodecRegistered(t *testing.T) {
+	if c := encoding.GetCodec(Name); c == nil || c.Name() != Name {
+		t.Fatalf("got codec %v, want %q", c, Name)
+	}
+}
+
+func TestStruct(t *testing.T) {
+	age := 18
+	in := loginRequest{
+		Username: "kratos",
+		Password: "secret",
+		Remember: true,
+		Age:      &age,
+		Scopes:   []string{"read", "write"},
+		Ignored:  "ignored",
+		Default:  1.5,
+		private:  "private",
+	}
+	data, err := codec{}.Marshal(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "Default=1.5&age=18&password=secret&remember=true&scope=read&scope=write&username=kratos"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	var out loginRequest
+	if err = (codec{}).Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	in.Ignored, in.private = "", ""
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("got %+v, want %+v", out, in)
+	}
+
+	if err = (codec{}).Unmarshal([]byte("remember=maybe"), &out); err == nil {
+		t.Error("invalid bool: got nil error")
+	}
+	if err = (codec{}).Unmarshal([]byte("a=b"), out); err == nil {
+		t.Error("non pointer target: got nil error")
+	}
+	if _, err = (codec{}).Marshal(1); err == nil {
+		t.Error("marshal an int: got nil error")
+	}
+}
+
+func TestValuesAndMap(t *testing.T) {
+	data, err := codec{}.Marshal(url.Values{"a": {"1", "2"}})
+	if err != nil || string(data) != "a=1&a=2" {
+		t.Errorf("url.Values: got %q, error %v", data, err)
+	}
+	data, err = codec{}.Marshal(map[string]string{"a": "1", "b": "2"})
+	if err != nil || string(data) != "a=1&b=2" {
+		t.Errorf("map: got %q, error %v", data, err)
+	}
+
+	var vs url.Values
+	if err = (codec{}).Unmarshal([]byte("a=1&a=2"), &vs); err != nil || !reflect.DeepEqual(vs, url.Values{"a": {"1", "2"}}) {
+		t.Errorf("url.Values: got %v, error %v", vs, err)
+	}
+	var m map[string]string
+	if err = (codec{}).Unmarshal([]byte("a=1&a=2&b=3"), &m); err != nil || !reflect.DeepEqual(m, map[string]string{"a": "1", "b": "3"}) {
+		t.Errorf("map: got %v, error %v", m, err)
+	}
+}
+
+func TestProtoMessage(t *testing.T) {
+	in := &complex.Complex{Id: 1, Labels: map[string]string{"env": "prod"}}
+	data, err := codec{}.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "id=1&labels%5Benv%5D=prod"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	// 绑定到子字段时传入的是 **T
+	var out *complex.Complex
+	if err = (codec{}).Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(in, out) {
+		t.Errorf("got %v, want %v", out, in)
+	}
+}
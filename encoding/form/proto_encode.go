@@ -0,0 +1,163 @@
+package form
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// EncodeValues encode a message into url values, 字段名使用 proto 名, 嵌套字段用 . 分隔.
+func EncodeValues(msg proto.Message) (url.Values, error) {
+	u := make(url.Values)
+	if msg == nil {
+		return u, nil
+	}
+	err := encodeByField(u, "", msg.ProtoReflect())
+	return u, err
+}
+
+func encodeByField(u url.Values, path string, m protoreflect.Message) (finalErr error) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		key := string(fd.Name())
+		if path != "" {
+			key = path + "." + key
+		}
+		switch {
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				value, err := encodeField(fd, list.Get(i))
+				if err != nil {
+					finalErr = err
+					return false
+				}
+				u.Add(key, value)
+			}
+		case fd.IsMap():
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				value, err := encodeField(fd.MapValue(), mv)
+				if err != nil {
+					finalErr = err
+					return false
+				}
+				u.Set(key+"["+k.String()+"]", value)
+				return true
+			})
+		case (fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind) && !isWellKnownType(fd.Message().FullName()):
+			if err := encodeByField(u, key, v.Message()); err != nil {
+				finalErr = err
+				return false
+			}
+		default:
+			value, err := encodeField(fd, v)
+			if err != nil {
+				finalErr = err
+				return false
+			}
+			u.Set(key, value)
+		}
+		return finalErr == nil
+	})
+	return
+}
+
+func encodeField(fd protoreflect.FieldDescriptor, v protoreflect.Value) (string, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return strconv.FormatBool(v.Bool()), nil
+	case protoreflect.EnumKind:
+		if fd.Enum().FullName() == "google.protobuf.NullValue" {
+			return nullStr, nil
+		}
+		if desc := fd.Enum().Values().ByNumber(v.Enum()); desc != nil {
+			return string(desc.Name()), nil
+		}
+		return strconv.FormatInt(int64(v.Enum()), 10), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case protoreflect.FloatKind:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32), nil
+	case protoreflect.DoubleKind:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), nil
+	case protoreflect.StringKind:
+		return v.String(), nil
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return encodeMessage(fd.Message(), v)
+	default:
+		return "", fmt.Errorf("unsupported field kind: %v", fd.Kind())
+	}
+}
+
+// encodeMessage 编码 well-known 类型, 其余类型(Struct, Value 等)编码为 json
+func encodeMessage(md protoreflect.MessageDescriptor, value protoreflect.Value) (string, error) {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		t, ok := value.Message().Interface().(*timestamppb.Timestamp)
+		if !ok {
+			return "", nil
+		}
+		return t.AsTime().Format(time.RFC3339Nano), nil
+	case "google.protobuf.Duration":
+		d, ok := value.Message().Interface().(*durationpb.Duration)
+		if !ok {
+			return "", nil
+		}
+		// 和 protojson 保持一致, 例如 90s, 1.500s
+		b, err := protojson.Marshal(d)
+		return strings.Trim(string(b), `"`), err
+	case "google.protobuf.BytesValue":
+		b, ok := value.Message().Interface().(interface{ GetValue() []byte })
+		if !ok {
+			return "", nil
+		}
+		return base64.StdEncoding.EncodeToString(b.GetValue()), nil
+	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue", "google.protobuf.Int64Value", "google.protobuf.Int32Value",
+		"google.protobuf.UInt64Value", "google.protobuf.UInt32Value", "google.protobuf.BoolValue", "google.protobuf.StringValue":
+		fd := md.Fields().ByName("value")
+		return encodeField(fd, value.Message().Get(fd))
+	case "google.protobuf.FieldMask":
+		m, ok := value.Message().Interface().(*fieldmaskpb.FieldMask)
+		if !ok {
+			return "", nil
+		}
+		return strings.Join(m.GetPaths(), ","), nil
+	default:
+		b, err := protojson.Marshal(value.Message().Interface())
+		return string(b), err
+	}
+}
+
+// EncodeFieldMask return field mask name=paths
+func EncodeFieldMask(m protoreflect.Message) (query string) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Kind() == protoreflect.MessageKind && fd.Message().FullName() == "google.protobuf.FieldMask" {
+			value, err := encodeMessage(fd.Message(), v)
+			if err == nil {
+				query = url.QueryEscape(string(fd.Name())) + "=" + url.QueryEscape(value)
+			}
+			return false
+		}
+		return true
+	})
+	return
+}
+
+func isWellKnownType(name protoreflect.FullName) bool {
+	return strings.HasPrefix(string(name), "google.protobuf.")
+}
@@ -0,0 +1,177 @@
+package form
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"kratos_c/internal/testdata/complex"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func decode(t *testing.T, query string) (*complex.Complex, error) {
+	t.Helper()
+	vs, err := url.ParseQuery(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := &complex.Complex{}
+	return in, DecodeValues(in, vs)
+}
+
+func TestDecodeValues(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC)
+	extra, _ := structpb.NewStruct(map[string]any{"a": "b"})
+	tests := []struct {
+		name  string
+		query string
+		want  *complex.Complex
+	}{
+		{"proto name", "id=1&user_name=kratos", &complex.Complex{Id: 1, UserName: "kratos"}},
+		{"json name", "userName=kratos&score=1.5", &complex.Complex{UserName: "kratos", Score: 1.5}},
+		{"repeated", "numbers=1&numbers=2&numbers=3", &complex.Complex{Numbers: []int32{1, 2, 3}}},
+		{"map", "labels[env]=prod&labels[zone]=a&codes[404]=not found", &complex.Complex{
+			Labels: map[string]string{"env": "prod", "zone": "a"},
+			Codes:  map[int32]string{404: "not found"},
+		}},
+		{"oneof", "email=a@b.c", &complex.Complex{Contact: &complex.Complex_Email{Email: "a@b.c"}}},
+		{"optional", "age=0", &complex.Complex{Age: proto.Int32(0)}},
+		{"enum name", "status=ACTIVE", &complex.Complex{Status: complex.Complex_ACTIVE}},
+		{"enum number", "status=2", &complex.Complex{Status: complex.Complex_DISABLED}},
+		{"nested", "child.name=c&child.tags=x&child.tags=y", &complex.Complex{Child: &complex.Child{Name: "c", Tags: []string{"x", "y"}}}},
+		{"bytes", "data=aGk=", &complex.Complex{Data: []byte("hi")}},
+		{"bytes url", "data=_-8", &complex.Complex{Data: []byte{0xff, 0xef}}},
+		{"timestamp", "created_at=2024-01-02T03:04:05.0000006Z", &complex.Complex{CreatedAt: timestamppb.New(created)}},
+		{"duration", "ttl=1m30s", &complex.Complex{Ttl: durationpb.New(90 * time.Second)}},
+		{"wrappers", "nickname=k&enabled=true", &complex.Complex{Nickname: wrapperspb.String("k"), Enabled: wrapperspb.Bool(true)}},
+		{"struct", `extra={"a":"b"}`, &complex.Complex{Extra: extra}},
+		{"field mask", "update_mask=userName,child.name, createdAt", &complex.Complex{
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"user_name", "child.name", "created_at"}},
+		}},
+		{"null message", "child=null", &complex.Complex{}},
+		{"empty value", "id=&child.name=", &complex.Complex{Child: &complex.Child{}}},
+		{"unknown field", "unknown=1&child.unknown=2", &complex.Complex{Child: &complex.Child{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decode(t, tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeValuesError(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		err   string
+	}{
+		{"oneof conflict", "email=a@b.c&phone=1", "oneof"},
+		{"map without key", "labels=prod", "requires a key"},
+		{"map too many values", "labels[env]=a&labels[env]=b", "too many values"},
+		{"map bad key", "codes[x]=a", "map key"},
+		{"too many values", "id=1&id=2", "too many values"},
+		{"bad int", "id=x", "id"},
+		{"bad enum", "status=UNKNOWN", "not a valid value"},
+		{"bad enum number", "status=9", "not a valid value"},
+		{"bad bytes", "data=***", "base64"},
+		{"bad timestamp", "created_at=yesterday", "created_at"},
+		{"bad duration", "ttl=forever", "ttl"},
+		{"bad struct", "extra=x", "extra"},
+		{"path through scalar", "id.value=1", "not a message"},
+		{"path through list", "numbers.value=1", "not a message"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vs, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = DecodeValues(&complex.Complex{}, vs)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want it to contain %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestEncodeValues(t *testing.T) {
+	in := &complex.Complex{
+		Id:         1,
+		UserName:   "kratos",
+		Numbers:    []int32{1, 2},
+		Labels:     map[string]string{"env": "prod"},
+		Codes:      map[int32]string{404: "not found"},
+		Contact:    &complex.Complex_Phone{Phone: "123"},
+		Status:     complex.Complex_ACTIVE,
+		CreatedAt:  timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		Ttl:        durationpb.New(1500 * time.Millisecond),
+		Nickname:   wrapperspb.String("k"),
+		Enabled:    wrapperspb.Bool(false),
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"user_name", "child.name"}},
+		Child:      &complex.Child{Name: "c", Tags: []string{"x"}},
+		Data:       []byte{0xff, 0xef},
+		Age:        proto.Int32(0),
+	}
+	got, err := EncodeValues(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := url.Values{
+		"id":          {"1"},
+		"user_name":   {"kratos"},
+		"numbers":     {"1", "2"},
+		"labels[env]": {"prod"},
+		"codes[404]":  {"not found"},
+		"phone":       {"123"},
+		"status":      {"ACTIVE"},
+		"created_at":  {"2024-01-02T03:04:05Z"},
+		"ttl":         {"1.500s"},
+		"nickname":    {"k"},
+		"enabled":     {"false"},
+		"update_mask": {"user_name,child.name"},
+		"child.name":  {"c"},
+		"child.tags":  {"x"},
+		"data":        {"/+8="},
+		"age":         {"0"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// 编码后再解码得到相同的消息
+	out := &complex.Complex{}
+	if err = DecodeValues(out, got); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(in, out) {
+		t.Errorf("round trip: got %v, want %v", out, in)
+	}
+
+	if got, err = EncodeValues(nil); err != nil || len(got) != 0 {
+		t.Errorf("nil message: got %v, error %v", got, err)
+	}
+}
+
+func TestEncodeFieldMask(t *testing.T) {
+	in := &complex.Complex{Id: 1, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"user_name", "child.name"}}}
+	if got, want := EncodeFieldMask(in.ProtoReflect()), "update_mask=user_name%2Cchild.name"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := EncodeFieldMask((&complex.Complex{Id: 1}).ProtoReflect()); got != "" {
+		t.Errorf("without field mask: got %q, want empty", got)
+	}
+}
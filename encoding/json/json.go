@@ -0,0 +1,69 @@
+package json
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"kratos_c/encoding"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Name is the name registered for the json codec.
+const Name = "json"
+
+var (
+	// MarshalOptions is a configurable JSON format marshaller.
+	MarshalOptions = protojson.MarshalOptions{
+		EmitUnpopulated: true,
+	}
+	// UnmarshalOptions is a configurable JSON format parser.
+	UnmarshalOptions = protojson.UnmarshalOptions{
+		DiscardUnknown: true,
+	}
+)
+
+func init() {
+	encoding.RegisterCodec(codec{})
+}
+
+// codec is a Codec implementation with json.
+type codec struct{}
+
+func (codec) Marshal(v any) ([]byte, error) {
+	switch m := v.(type) {
+	case json.Marshaler:
+		return m.MarshalJSON()
+	case proto.Message:
+		return MarshalOptions.Marshal(m)
+	default:
+		return json.Marshal(m)
+	}
+}
+
+func (codec) Unmarshal(data []byte, v any) error {
+	switch m := v.(type) {
+	case json.Unmarshaler:
+		return m.UnmarshalJSON(data)
+	case proto.Message:
+		return UnmarshalOptions.Unmarshal(data, m)
+	default:
+		// 绑定到消息类型的子字段时 v 是 **T, 需要先分配内存
+		rv := reflect.ValueOf(v)
+		for rv.Kind() == reflect.Pointer && rv.Elem().Kind() == reflect.Pointer {
+			if rv.Elem().IsNil() {
+				rv.Elem().Set(reflect.New(rv.Elem().Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		if m, ok := rv.Interface().(proto.Message); ok {
+			return UnmarshalOptions.Unmarshal(data, m)
+		}
+		return json.Unmarshal(data, v)
+	}
+}
+
+func (codec) Name() string {
+	return Name
+}
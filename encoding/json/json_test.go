@@ -0,0 +1,107 @@
+package json
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"kratos_c/encoding"
+	"kratos_c/internal/testdata/complex"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type user struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// custom 实现了 json.Marshaler 与 json.Unmarshaler
+type custom struct {
+	value string
+}
+
+func (c *custom) MarshalJSON() ([]byte, error) {
+	return []byte(`"custom:` + c.value + `"`), nil
+}
+
+func (c *custom) UnmarshalJSON(data []byte) error {
+	c.value = strings.Trim(string(data), `"`)
+	return nil
+}
+
+func TestCodecRegistered(t *testing.T) {
+	if c := encoding.GetCodec(Name); c == nil || c.Name() != Name {
+		t.Fatalf("got codec %v, want %q", c, Name)
+	}
+}
+
+func TestStruct(t *testing.T) {
+	data, err := codec{}.Marshal(&user{ID: 1, Name: "kratos"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `{"id":1,"name":"kratos"}`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	var out user
+	if err = (codec{}).Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if want := (user{ID: 1, Name: "kratos"}); out != want {
+		t.Errorf("got %+v, want %+v", out, want)
+	}
+}
+
+func TestMarshaler(t *testing.T) {
+	data, err := codec{}.Marshal(&custom{value: "a"})
+	if err != nil || string(data) != `"custom:a"` {
+		t.Errorf("got %s, error %v", data, err)
+	}
+	var out custom
+	if err = (codec{}).Unmarshal([]byte(`"b"`), &out); err != nil || out.value != "b" {
+		t.Errorf("got %q, error %v", out.value, err)
+	}
+}
+
+func TestProtoMessage(t *testing.T) {
+	in := &complex.Complex{Id: 1, UserName: "kratos", Status: complex.Complex_ACTIVE}
+	data, err := codec{}.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 使用 json 名并输出零值字段
+	for _, want := range []string{`"userName":"kratos"`, `"status":"ACTIVE"`, `"numbers":[]`} {
+		if !strings.Contains(strings.ReplaceAll(string(data), " ", ""), want) {
+			t.Errorf("got %s, want it to contain %s", data, want)
+		}
+	}
+
+	out := &complex.Complex{}
+	// 忽略未知字段
+	if err = (codec{}).Unmarshal([]byte(`{"id":"1","userName":"kratos","status":"ACTIVE","unknown":1}`), out); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(in, out) {
+		t.Errorf("got %v, want %v", out, in)
+	}
+
+	// 绑定到子字段时传入的是 **T
+	var ptr *complex.Complex
+	if err = (codec{}).Unmarshal(data, &ptr); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(in, ptr) {
+		t.Errorf("got %v, want %v", ptr, in)
+	}
+}
+
+func TestMap(t *testing.T) {
+	var out map[string]any
+	if err := (codec{}).Unmarshal([]byte(`{"a":[1,"b"]}`), &out); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]any{"a": []any{float64(1), "b"}}; !reflect.DeepEqual(out, want) {
+		t.Errorf("got %v, want %v", out, want)
+	}
+}
@@ -0,0 +1,64 @@
+// Package proto defines the protobuf codec. Importing this package will
+// register the codec.
+package proto
+
+import (
+	"errors"
+	"reflect"
+
+	"kratos_c/encoding"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Name is the name registered for the proto codec.
+const Name = "proto"
+
+func init() {
+	encoding.RegisterCodec(codec{})
+}
+
+// codec is a Codec implementation with protobuf. It is the default codec for Transport.
+type codec struct{}
+
+func (codec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, errors.New("proto: marshal value is not a proto.Message")
+	}
+	return proto.Marshal(m)
+}
+
+func (codec) Unmarshal(data []byte, v any) error {
+	m, err := getProtoMessage(v)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, m)
+}
+
+func (codec) Name() string {
+	return Name
+}
+
+// getProtoMessage 支持 *T 以及绑定子字段时的 **T
+func getProtoMessage(v any) (proto.Message, error) {
+	if msg, ok := v.(proto.Message); ok {
+		return msg, nil
+	}
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Pointer || val.IsNil() {
+		return nil, errors.New("proto: unmarshal value is not a pointer")
+	}
+	for val.Kind() == reflect.Pointer && val.Elem().Kind() == reflect.Pointer {
+		if val.Elem().IsNil() {
+			val.Elem().Set(reflect.New(val.Elem().Type().Elem()))
+		}
+		val = val.Elem()
+	}
+	msg, ok := val.Interface().(proto.Message)
+	if !ok {
+		return nil, errors.New("proto: unmarshal value is not a proto.Message")
+	}
+	return msg, nil
+}
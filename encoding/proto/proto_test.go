@@ -0,0 +1,53 @@
+package proto
+
+import (
+	"testing"
+
+	"kratos_c/encoding"
+	"kratos_c/internal/testdata/complex"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCodecRegistered(t *testing.T) {
+	if c := encoding.GetCodec(Name); c == nil || c.Name() != Name {
+		t.Fatalf("got codec %v, want %q", c, Name)
+	}
+}
+
+func TestCodec(t *testing.T) {
+	in := &complex.Complex{Id: 1, UserName: "kratos", Labels: map[string]string{"env": "prod"}}
+	data, err := codec{}.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &complex.Complex{}
+	if err = (codec{}).Unmarshal(data, out); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(in, out) {
+		t.Errorf("got %v, want %v", out, in)
+	}
+
+	// 绑定到子字段时传入的是 **T
+	var ptr *complex.Complex
+	if err = (codec{}).Unmarshal(data, &ptr); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(in, ptr) {
+		t.Errorf("got %v, want %v", ptr, in)
+	}
+}
+
+func TestNotProtoMessage(t *testing.T) {
+	if _, err := (codec{}).Marshal(struct{}{}); err == nil {
+		t.Error("marshal a struct: got nil error")
+	}
+	var s struct{}
+	if err := (codec{}).Unmarshal(nil, &s); err == nil {
+		t.Error("unmarshal into a struct: got nil error")
+	}
+	if err := (codec{}).Unmarshal(nil, complex.Complex{}); err == nil {
+		t.Error("unmarshal into a non pointer: got nil error")
+	}
+}
@@ -0,0 +1,39 @@
+package xml
+
+import (
+	"testing"
+
+	"kratos_c/encoding"
+)
+
+type user struct {
+	ID   int64  `xml:"id,attr"`
+	Name string `xml:"name"`
+}
+
+func TestCodecRegistered(t *testing.T) {
+	if c := encoding.GetCodec(Name); c == nil || c.Name() != Name {
+		t.Fatalf("got codec %v, want %q", c, Name)
+	}
+}
+
+func TestCodec(t *testing.T) {
+	in := user{ID: 1, Name: "kratos"}
+	data, err := codec{}.Marshal(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `<user id="1"><name>kratos</name></user>`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	var out user
+	if err = (codec{}).Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Errorf("got %+v, want %+v", out, in)
+	}
+	if err = (codec{}).Unmarshal([]byte("<user>"), &out); err == nil {
+		t.Error("invalid xml: got nil error")
+	}
+}
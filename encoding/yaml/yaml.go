@@ -0,0 +1,56 @@
+package yaml
+
+import (
+	"encoding/json"
+
+	"kratos_c/encoding"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+// Name is the name registered for the yaml codec.
+const Name = "yaml"
+
+func init() {
+	encoding.RegisterCodec(codec{})
+}
+
+// codec is a Codec implementation with yaml.
+type codec struct{}
+
+func (codec) Marshal(v any) ([]byte, error) {
+	// proto 消息先转换为 JSON, 保持与 json 编码一致的字段命名
+	if m, ok := v.(proto.Message); ok {
+		data, err := protojson.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+		var out any
+		if err = yaml.Unmarshal(data, &out); err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(out)
+	}
+	return yaml.Marshal(v)
+}
+
+func (codec) Unmarshal(data []byte, v any) error {
+	if m, ok := v.(proto.Message); ok {
+		var in any
+		if err := yaml.Unmarshal(data, &in); err != nil {
+			return err
+		}
+		js, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(js, m)
+	}
+	return yaml.Unmarshal(data, v)
+}
+
+func (codec) Name() string {
+	return Name
+}
@@ -0,0 +1,66 @@
+package yaml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"kratos_c/encoding"
+	"kratos_c/internal/testdata/complex"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCodecRegistered(t *testing.T) {
+	if c := encoding.GetCodec(Name); c == nil || c.Name() != Name {
+		t.Fatalf("got codec %v, want %q", c, Name)
+	}
+}
+
+func TestMap(t *testing.T) {
+	in := map[string]any{"name": "kratos", "ports": []any{8000, 9000}}
+	data, err := codec{}.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out map[string]any
+	if err = (codec{}).Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("got %v, want %v", out, in)
+	}
+}
+
+func TestProtoMessage(t *testing.T) {
+	in := &complex.Complex{Id: 1, UserName: "kratos", Labels: map[string]string{"env": "prod"}, Status: complex.Complex_ACTIVE}
+	data, err := codec{}.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 字段名与 json 编码一致
+	if !strings.Contains(string(data), "userName: kratos") {
+		t.Errorf("got\n%s\nwant the json field name", data)
+	}
+	out := &complex.Complex{}
+	if err = (codec{}).Unmarshal(data, out); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(in, out) {
+		t.Errorf("got %v, want %v", out, in)
+	}
+
+	// 支持 proto 名并忽略未知字段
+	out = &complex.Complex{}
+	if err = (codec{}).Unmarshal([]byte("user_name: kratos\nunknown: 1\nchild:\n  tags: [a, b]\n"), out); err != nil {
+		t.Fatal(err)
+	}
+	want := &complex.Complex{UserName: "kratos", Child: &complex.Child{Tags: []string{"a", "b"}}}
+	if !proto.Equal(out, want) {
+		t.Errorf("got %v, want %v", out, want)
+	}
+
+	if err = (codec{}).Unmarshal([]byte("id: [1"), out); err == nil {
+		t.Error("invalid yaml: got nil error")
+	}
+}
@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIs(t *testing.T) {
+	notFound := NotFound("USER_NOT_FOUND", "user not found")
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{"same", notFound, notFound, true},
+		{"different message", NotFound("USER_NOT_FOUND", "no such user"), notFound, true},
+		{"different metadata", notFound.WithMetadata(map[string]string{"id": "1"}), notFound, true},
+		{"wrapped", fmt.Errorf("get user: %w", notFound), notFound, true},
+		{"with cause", notFound.WithCause(context.Canceled), notFound, true},
+		{"cause", notFound.WithCause(context.Canceled), context.Canceled, true},
+		{"different reason", NotFound("ORDER_NOT_FOUND", "order not found"), notFound, false},
+		{"different code", BadRequest("USER_NOT_FOUND", "user not found"), notFound, false},
+		{"no reason", New(500, UnknownReason, "a"), New(500, UnknownReason, "b"), true},
+		{"no reason different code", New(500, UnknownReason, "a"), New(503, UnknownReason, "a"), false},
+		{"plain error", fmt.Errorf("user not found"), notFound, false},
+	}
+	for _, tt := range tests {
+		if got := Is(tt.err, tt.target); got != tt.want {
+			t.Errorf("%s: Is() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    int32
+		reason  string
+		message string
+	}{
+		{"error", Forbidden("DENIED", "denied"), 403, "DENIED", "denied"},
+		{"wrapped", fmt.Errorf("wrap: %w", Conflict("EXISTS", "exists")), 409, "EXISTS", "exists"},
+		{"plain", fmt.Errorf("boom"), UnknownCode, UnknownReason, "boom"},
+		{"grpc status", status.Error(codes.Unavailable, "down"), 503, UnknownReason, "down"},
+		{"grpc status canceled", status.Error(codes.Canceled, "canceled"), 499, UnknownReason, "canceled"},
+	}
+	for _, tt := range tests {
+		e := FromError(tt.err)
+		if e.Code != tt.code || e.Reason != tt.reason || e.Message != tt.message {
+			t.Errorf("%s: FromError() = %d %q %q, want %d %q %q", tt.name, e.Code, e.Reason, e.Message, tt.code, tt.reason, tt.message)
+		}
+	}
+	if FromError(nil) != nil {
+		t.Errorf("FromError(nil) should be nil")
+	}
+}
+
+func TestGRPCStatus(t *testing.T) {
+	tests := []struct {
+		err  *Error
+		code codes.Code
+	}{
+		{BadRequest("INVALID", "invalid"), codes.InvalidArgument},
+		{Unauthorized("UNAUTHORIZED", "unauthorized"), codes.Unauthenticated},
+		{NotFound("NOT_FOUND", "not found"), codes.NotFound},
+		{ServiceUnavailable("DOWN", "down"), codes.Unavailable},
+		{InternalServer("INTERNAL", "internal"), codes.Internal},
+	}
+	for _, tt := range tests {
+		err := tt.err.WithMetadata(map[string]string{"k": "v"})
+		gs := err.GRPCStatus()
+		if gs.Code() != tt.code {
+			t.Errorf("%s: grpc code = %v, want %v", tt.err.Reason, gs.Code(), tt.code)
+		}
+		// 经过 gRPC status 往返后 Code, Reason, Message 和 Metadata 保持不变
+		back := FromError(gs.Err())
+		if back.Code != err.Code || back.Reason != err.Reason || back.Message != err.Message || back.Metadata["k"] != "v" {
+			t.Errorf("%s: round trip = %v, want %v", tt.err.Reason, back, err)
+		}
+		if !Is(back, tt.err) {
+			t.Errorf("%s: round trip should match the original error", tt.err.Reason)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package cpu
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	interval = 500 * time.Millisecond
+	// decay 为指数移动平均的衰减系数, 平滑瞬时的 CPU 抖动
+	decay = 0.95
+)
+
+var (
+	once  sync.Once
+	usage atomic.Uint64
+)
+
+// Usage 返回平滑后的 CPU 使用率, 单位为千分比(0 ~ 1000).
+// 第一次调用时启动后台采样, 无法读取 CPU 统计的平台上始终返回 0.
+func Usage() uint64 {
+	once.Do(func() {
+		s := newStat()
+		if s == nil {
+			return
+		}
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			var cpu float64
+			for range ticker.C {
+				cur, ok := s.sample()
+				if !ok {
+					continue
+				}
+				cpu = cpu*decay + float64(cur)*(1-decay)
+				usage.Store(uint64(cpu))
+			}
+		}()
+	})
+	return usage.Load()
+}
+
+// stat 通过相邻两次采样的差值计算 CPU 使用率, 优先使用 cgroup v2 的统计.
+type stat struct {
+	read      func() (busy, total uint64, ok bool)
+	lastBusy  uint64
+	lastTotal uint64
+}
+
+func newStat() *stat {
+	for _, read := range []func() (uint64, uint64, bool){readCgroup(), readProcStat} {
+		if read == nil {
+			continue
+		}
+		if busy, total, ok := read(); ok {
+			return &stat{read: read, lastBusy: busy, lastTotal: total}
+		}
+	}
+	return nil
+}
+
+func (s *stat) sample() (uint64, bool) {
+	busy, total, ok := s.read()
+	if !ok || total <= s.lastTotal {
+		return 0, false
+	}
+	if busy < s.lastBusy {
+		// 计数器被重置(例如 cgroup 重建), 以本次采样作为新的起点
+		s.lastBusy, s.lastTotal = busy, total
+		return 0, false
+	}
+	u := (busy - s.lastBusy) * 1000 / (total - s.lastTotal)
+	s.lastBusy, s.lastTotal = busy, total
+	if u > 1000 {
+		u = 1000
+	}
+	return u, true
+}
+
+// readProcStat 读取 /proc/stat 中的整机 CPU 时间
+func readProcStat() (busy, total uint64, ok bool) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return 0, 0, false
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 5 || fields[0] != "cpu" {
+			continue
+		}
+		var idle uint64
+		for i, field := range fields[1:] {
+			v, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return 0, 0, false
+			}
+			total += v
+			// idle 与 iowait
+			if i == 3 || i == 4 {
+				idle += v
+			}
+		}
+		return total - idle, total, true
+	}
+	return 0, 0, false
+}
+
+// readCgroup 返回读取 cgroup v2 CPU 用量的函数, 可用时间为 cpu.max 中的配额, 没有配额时按 CPU 核数计算
+func readCgroup() func() (uint64, uint64, bool) {
+	quota := float64(runtime.NumCPU())
+	if data, err := os.ReadFile("/sys/fs/cgroup/cpu.max"); err == nil {
+		fields := strings.Fields(string(data))
+		if len(fields) == 2 && fields[0] != "max" {
+			q, err1 := strconv.ParseFloat(fields[0], 64)
+			p, err2 := strconv.ParseFloat(fields[1], 64)
+			if err1 == nil && err2 == nil && p > 0 {
+				quota = q / p
+			}
+		}
+	}
+	start := time.Now()
+	return func() (uint64, uint64, bool) {
+		data, err := os.ReadFile("/sys/fs/cgroup/cpu.stat")
+		if err != nil {
+			return 0, 0, false
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 2 && fields[0] == "usage_usec" {
+				used, err := strconv.ParseUint(fields[1], 10, 64)
+				if err != nil {
+					return 0, 0, false
+				}
+				// 可用的 CPU 时间 = 经过的时间 * 配额
+				available := uint64(float64(time.Since(start).Microseconds()) * quota)
+				return used, available, true
+			}
+		}
+		return 0, 0, false
+	}
+}
@@ -0,0 +1,70 @@
+package cpu
+
+import (
+	"testing"
+)
+
+type sample struct {
+	busy, total uint64
+}
+
+// testStat 依次返回 samples 中的采样值
+func testStat(samples ...sample) *stat {
+	s := &stat{lastBusy: samples[0].busy, lastTotal: samples[0].total}
+	samples = samples[1:]
+	s.read = func() (uint64, uint64, bool) {
+		if len(samples) == 0 {
+			return 0, 0, false
+		}
+		cur := samples[0]
+		samples = samples[1:]
+		return cur.busy, cur.total, true
+	}
+	return s
+}
+
+func TestSample(t *testing.T) {
+	s := testStat(
+		sample{100, 1000},
+		sample{150, 1100}, // 50 / 100
+		sample{150, 1100}, // total 没有增长
+		sample{250, 1200}, // 100 / 100
+		sample{500, 1300}, // 超过 1000 时截断
+		sample{10, 1400},  // busy 计数器被重置
+		sample{60, 1500},  // 以重置后的采样为起点
+	)
+	want := []struct {
+		usage uint64
+		ok    bool
+	}{
+		{500, true},
+		{0, false},
+		{1000, true},
+		{1000, true},
+		{0, false},
+		{500, true},
+		{0, false},
+	}
+	for i, w := range want {
+		u, ok := s.sample()
+		if u != w.usage || ok != w.ok {
+			t.Errorf("sample %d: got (%d, %v), want (%d, %v)", i, u, ok, w.usage, w.ok)
+		}
+	}
+}
+
+func TestReadProcStat(t *testing.T) {
+	busy, total, ok := readProcStat()
+	if !ok {
+		t.Skip("/proc/stat is not available")
+	}
+	if busy > total || total == 0 {
+		t.Errorf("got busy %d total %d", busy, total)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	if u := Usage(); u > 1000 {
+		t.Errorf("got usage %d, want at most 1000", u)
+	}
+}
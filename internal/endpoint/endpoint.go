@@ -0,0 +1,31 @@
+package endpoint
+
+import "net/url"
+
+// NewEndpoint new an Endpoint URL.
+func NewEndpoint(scheme, host string) *url.URL {
+	return &url.URL{Scheme: scheme, Host: host}
+}
+
+// ParseEndpoint 从实例的端点列表中取出指定 scheme 的地址.
+func ParseEndpoint(endpoints []string, scheme string) (string, error) {
+	for _, e := range endpoints {
+		u, err := url.Parse(e)
+		if err != nil {
+			return "", err
+		}
+		if u.Scheme == scheme {
+			return u.Host, nil
+		}
+	}
+	return "", nil
+}
+
+// Scheme is the scheme of endpoint url.
+// examples: scheme="http",isSecure=true get "https"
+func Scheme(scheme string, isSecure bool) string {
+	if isSecure {
+		return scheme + "s"
+	}
+	return scheme
+}
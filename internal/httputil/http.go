@@ -0,0 +1,54 @@
+package httputil
+
+import "strings"
+
+const (
+	baseContentType = "application"
+)
+
+// 常见的非标准 content-subtype 与 Codec 名称的对应关系
+var subtypeAliases = map[string]string{
+	"x-protobuf": "proto",
+	"protobuf":   "proto",
+	"x-yaml":     "yaml",
+}
+
+// ContentType returns the content-type with base prefix.
+func ContentType(subtype string) string {
+	return baseContentType + "/" + subtype
+}
+
+// ContentSubtype returns the content-subtype for the given content-type
+// according to rfc7231, e.g. "application/json; charset=utf-8" returns "json".
+// An invalid content-type returns "".
+func ContentSubtype(contentType string) string {
+	left := strings.Index(contentType, "/")
+	if left == -1 {
+		return ""
+	}
+	right := strings.Index(contentType, ";")
+	if right == -1 {
+		right = len(contentType)
+	}
+	if right < left {
+		return ""
+	}
+	subtype := strings.ToLower(strings.TrimSpace(contentType[left+1 : right]))
+	if alias, ok := subtypeAliases[subtype]; ok {
+		return alias
+	}
+	return subtype
+}
+
+// MediaTypes 将 Accept 之类的头部拆分为按出现顺序排列的媒体类型列表, 忽略 q 等参数.
+func MediaTypes(values []string) []string {
+	var types []string
+	for _, value := range values {
+		for _, typ := range strings.Split(value, ",") {
+			if typ = strings.TrimSpace(typ); typ != "" {
+				types = append(types, typ)
+			}
+		}
+	}
+	return types
+}
@@ -1,24 +1,70 @@
 package matcher
 
 import (
+	"path"
+	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"kratos_c/middleware"
 )
 
+// RegexPrefix 以它开头的 selector 按正则表达式匹配, 例如 "~^/api\.v1\..*/List".
+const RegexPrefix = "~"
+
+// maxCached 限制缓存的 operation 数量, 超过后不再缓存新的 operation, 只是每次重新计算.
+const maxCached = 4096
+
+// Matcher is a middleware matcher.
+//
+// selector 支持以下形式:
+//   - 精确匹配: "/pkg.Service/Method"
+//   - 前缀匹配: 只在末尾有 "*", 例如 "/pkg.Service/*", "/pkg.*", "*" 可以跨越 "/"
+//   - 段内通配: 其他位置含有 "*", 例如 "/pkg.*/Get*", "*" 不跨越 "/", 语义同 path.Match
+//   - 正则匹配: 以 RegexPrefix 开头, 例如 "~/pkg\.Service/(Get|List).*"
+//
+// Match 返回的顺序是确定的: 先是 Use 的默认中间件; 如果存在精确匹配, 只追加精确匹配的中间件;
+// 否则依次追加所有命中的前缀 (越长越靠前)、段内通配 (按添加顺序) 和正则 (按添加顺序).
+// 重复 Add 同一个 selector 会覆盖之前的中间件, 顺序保持第一次添加时的位置.
 type Matcher interface {
 	Use(ms ...middleware.Middleware)
 	Add(selector string, ms ...middleware.Middleware)
 	Match(operation string) []middleware.Middleware
 }
 
+type entry struct {
+	selector string
+	ms       []middleware.Middleware
+}
+
+type regexEntry struct {
+	entry
+	re *regexp.Regexp
+}
+
+// cached 是某一代配置下的匹配结果, gen 和当前不一致时视为失效
+type cached struct {
+	gen uint64
+	ms  []middleware.Middleware
+}
+
 type matcher struct {
-	prefix   []string
+	mu       sync.RWMutex
 	defaults []middleware.Middleware
 	matches  map[string][]middleware.Middleware
+	prefix   []entry // 按前缀长度从长到短排列
+	globs    []entry
+	regexes  []regexEntry
+
+	// cache 缓存每个 operation 的匹配结果, Use/Add 时 gen 加一并清空
+	gen   atomic.Uint64
+	cache sync.Map
+	size  atomic.Int64
 }
 
+// New new a middleware matcher.
 func New() Matcher {
 	return &matcher{
 		matches: make(map[string][]middleware.Middleware),
@@ -26,32 +72,99 @@ func New() Matcher {
 }
 
 func (m *matcher) Use(ms ...middleware.Middleware) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.defaults = ms
+	m.reset()
 }
 
+// Add 注册 selector 对应的中间件, selector 非法时 panic, 和 regexp.MustCompile 一致.
 func (m *matcher) Add(selector string, ms ...middleware.Middleware) {
-	if strings.HasSuffix(selector, "*") {
-		selector = strings.TrimSuffix(selector, "*")
-		m.prefix = append(m.prefix, selector)
-		sort.Slice(m.prefix, func(i, j int) bool { return m.prefix[i] > m.prefix[j] })
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	defer m.reset()
+	switch {
+	case strings.HasPrefix(selector, RegexPrefix):
+		expr := strings.TrimPrefix(selector, RegexPrefix)
+		for i := range m.regexes {
+			if m.regexes[i].selector == expr {
+				m.regexes[i].ms = ms
+				return
+			}
+		}
+		m.regexes = append(m.regexes, regexEntry{entry: entry{selector: expr, ms: ms}, re: regexp.MustCompile(expr)})
+	case strings.Contains(strings.TrimSuffix(selector, "*"), "*"):
+		if _, err := path.Match(selector, ""); err != nil {
+			panic("matcher: invalid selector " + selector + ": " + err.Error())
+		}
+		m.globs = upsert(m.globs, selector, ms)
+	case strings.HasSuffix(selector, "*"):
+		m.prefix = upsert(m.prefix, strings.TrimSuffix(selector, "*"), ms)
+		sort.SliceStable(m.prefix, func(i, j int) bool { return len(m.prefix[i].selector) > len(m.prefix[j].selector) })
+	default:
+		m.matches[selector] = ms
 	}
-	m.matches[selector] = ms
 }
 
 func (m *matcher) Match(operation string) []middleware.Middleware {
+	if v, ok := m.cache.Load(operation); ok {
+		if c := v.(*cached); c.gen == m.gen.Load() {
+			return c.ms
+		}
+	}
+	// 只持有读锁计算, 并发的请求不会互相阻塞; gen 在读锁内读取, 保证和计算结果对应
+	m.mu.RLock()
+	c := &cached{gen: m.gen.Load(), ms: m.match(operation)}
+	m.mu.RUnlock()
+	if m.size.Load() < maxCached {
+		if _, loaded := m.cache.Swap(operation, c); !loaded {
+			m.size.Add(1)
+		}
+	}
+	return c.ms
+}
+
+func (m *matcher) match(operation string) []middleware.Middleware {
 	// 添加默认的
 	ms := make([]middleware.Middleware, 0, len(m.defaults))
 	if len(m.defaults) > 0 {
 		ms = append(ms, m.defaults...)
 	}
+	// 精确匹配优先, 命中后不再看其他 selector
 	if next, ok := m.matches[operation]; ok {
-		ms = append(ms, next...)
-	} else {
-		for _, prefix := range m.prefix {
-			if strings.HasPrefix(operation, prefix) {
-				ms = append(ms, m.matches[prefix]...)
-			}
+		return append(ms, next...)
+	}
+	for _, e := range m.prefix {
+		if strings.HasPrefix(operation, e.selector) {
+			ms = append(ms, e.ms...)
+		}
+	}
+	for _, e := range m.globs {
+		if ok, _ := path.Match(e.selector, operation); ok {
+			ms = append(ms, e.ms...)
+		}
+	}
+	for _, e := range m.regexes {
+		if e.re.MatchString(operation) {
+			ms = append(ms, e.ms...)
+		}
+	}
+	return ms
+}
+
+// reset 使缓存失效, 调用时必须持有写锁.
+func (m *matcher) reset() {
+	m.gen.Add(1)
+	m.cache.Clear()
+	m.size.Store(0)
+}
+
+func upsert(entries []entry, selector string, ms []middleware.Middleware) []entry {
+	for i := range entries {
+		if entries[i].selector == selector {
+			entries[i].ms = ms
+			return entries
 		}
 	}
-	return nil
+	return append(entries, entry{selector: selector, ms: ms})
 }
@@ -0,0 +1,129 @@
+package matcher
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"kratos_c/middleware"
+)
+
+func named(name string) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			return handler(ctx, append(req.([]string), name))
+		}
+	}
+}
+
+// names 依次执行中间件, 返回它们的名字
+func names(ms []middleware.Middleware) []string {
+	reply, _ := middleware.Chain(ms...)(func(_ context.Context, req any) (any, error) {
+		return req, nil
+	})(context.Background(), []string{})
+	return reply.([]string)
+}
+
+func TestMatch(t *testing.T) {
+	m := New()
+	m.Use(named("default"))
+	m.Add("/*/List*", named("glob1"))
+	m.Add("/pkg.*", named("pkg"))
+	m.Add(`~^/pkg\.Service/(List|Delete)`, named("regex1"))
+	m.Add("/pkg.Service/*", named("service"))
+	m.Add("/pkg.*/ListBooks", named("glob2"))
+	m.Add(`~Books$`, named("regex2"))
+	m.Add("/pkg.Service/Get", named("exact"))
+
+	tests := []struct {
+		name      string
+		operation string
+		want      []string
+	}{
+		{
+			name:      "defaults only",
+			operation: "/other.Service/Get",
+			want:      []string{"default"},
+		},
+		{
+			name:      "exact suppresses prefix glob and regex",
+			operation: "/pkg.Service/Get",
+			want:      []string{"default", "exact"},
+		},
+		{
+			name:      "longest prefix first",
+			operation: "/pkg.Service/Update",
+			want:      []string{"default", "service", "pkg"},
+		},
+		{
+			name:      "prefix then glob then regex in insertion order",
+			operation: "/pkg.Service/ListBooks",
+			want:      []string{"default", "service", "pkg", "glob1", "glob2", "regex1", "regex2"},
+		},
+		{
+			name:      "glob does not cross segments",
+			operation: "/a/b/ListBooks",
+			want:      []string{"default", "regex2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 第二次取缓存, 结果必须一致
+			for range 2 {
+				if got := names(m.Match(tt.operation)); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Match(%q) = %v, want %v", tt.operation, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestAddReplacesInPlace(t *testing.T) {
+	m := New()
+	m.Add("/*/Get", named("glob1"))
+	m.Add("/*/G*", named("glob2"))
+	m.Add("~Get", named("regex1"))
+	m.Add("~^/", named("regex2"))
+	m.Add("/*/Get", named("glob1-new"))
+	m.Add("~Get", named("regex1-new"))
+
+	want := []string{"glob1-new", "glob2", "regex1-new", "regex2"}
+	if got := names(m.Match("/pkg.Service/Get")); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	m := New()
+	const op = "/pkg.Service/Get"
+	if got := names(m.Match(op)); len(got) != 0 {
+		t.Fatalf("got %v, want empty", got)
+	}
+
+	m.Use(named("default"))
+	if got, want := names(m.Match(op)), []string{"default"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after Use: got %v, want %v", got, want)
+	}
+
+	m.Add("/pkg.Service/*", named("service"))
+	if got, want := names(m.Match(op)), []string{"default", "service"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after Add: got %v, want %v", got, want)
+	}
+
+	m.Add(op, named("exact"))
+	if got, want := names(m.Match(op)), []string{"default", "exact"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after Add exact: got %v, want %v", got, want)
+	}
+}
+
+func BenchmarkMatch(b *testing.B) {
+	m := New()
+	m.Use(named("default"))
+	m.Add("/pkg.Service/*", named("service"))
+	m.Add(`~^/pkg\.Service/(List|Delete)`, named("regex"))
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m.Match("/pkg.Service/ListBooks")
+		}
+	})
+}
@@ -0,0 +1,458 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.2
+// source: complex.proto
+
+package complex
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Complex_Status int32
+
+const (
+	Complex_STATUS_UNSPECIFIED Complex_Status = 0
+	Complex_ACTIVE             Complex_Status = 1
+	Complex_DISABLED           Complex_Status = 2
+)
+
+// Enum value maps for Complex_Status.
+var (
+	Complex_Status_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "ACTIVE",
+		2: "DISABLED",
+	}
+	Complex_Status_value = map[string]int32{
+		"STATUS_UNSPECIFIED": 0,
+		"ACTIVE":             1,
+		"DISABLED":           2,
+	}
+)
+
+func (x Complex_Status) Enum() *Complex_Status {
+	p := new(Complex_Status)
+	*p = x
+	return p
+}
+
+func (x Complex_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Complex_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_complex_proto_enumTypes[0].Descriptor()
+}
+
+func (Complex_Status) Type() protoreflect.EnumType {
+	return &file_complex_proto_enumTypes[0]
+}
+
+func (x Complex_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Complex_Status.Descriptor instead.
+func (Complex_Status) EnumDescriptor() ([]byte, []int) {
+	return file_complex_proto_rawDescGZIP(), []int{0, 0}
+}
+
+// Complex 覆盖编解码需要处理的各种字段类型, 只用于测试.
+type Complex struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserName string                 `protobuf:"bytes,2,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	Numbers  []int32                `protobuf:"varint,3,rep,packed,name=numbers,proto3" json:"numbers,omitempty"`
+	Labels   map[string]string      `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Codes    map[int32]string       `protobuf:"bytes,5,rep,name=codes,proto3" json:"codes,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Types that are valid to be assigned to Contact:
+	//
+	//	*Complex_Email
+	//	*Complex_Phone
+	Contact       isComplex_Contact       `protobuf_oneof:"contact"`
+	Status        Complex_Status          `protobuf:"varint,8,opt,name=status,proto3,enum=testdata.Complex_Status" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp  `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Ttl           *durationpb.Duration    `protobuf:"bytes,10,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	Nickname      *wrapperspb.StringValue `protobuf:"bytes,11,opt,name=nickname,proto3" json:"nickname,omitempty"`
+	Enabled       *wrapperspb.BoolValue   `protobuf:"bytes,12,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	UpdateMask    *fieldmaskpb.FieldMask  `protobuf:"bytes,13,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	Extra         *structpb.Struct        `protobuf:"bytes,14,opt,name=extra,proto3" json:"extra,omitempty"`
+	Child         *Child                  `protobuf:"bytes,15,opt,name=child,proto3" json:"child,omitempty"`
+	Data          []byte                  `protobuf:"bytes,16,opt,name=data,proto3" json:"data,omitempty"`
+	Age           *int32                  `protobuf:"varint,17,opt,name=age,proto3,oneof" json:"age,omitempty"`
+	Score         float64                 `protobuf:"fixed64,18,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Complex) Reset() {
+	*x = Complex{}
+	mi := &file_complex_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Complex) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Complex) ProtoMessage() {}
+
+func (x *Complex) ProtoReflect() protoreflect.Message {
+	mi := &file_complex_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Complex.ProtoReflect.Descriptor instead.
+func (*Complex) Descriptor() ([]byte, []int) {
+	return file_complex_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Complex) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Complex) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *Complex) GetNumbers() []int32 {
+	if x != nil {
+		return x.Numbers
+	}
+	return nil
+}
+
+func (x *Complex) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Complex) GetCodes() map[int32]string {
+	if x != nil {
+		return x.Codes
+	}
+	return nil
+}
+
+func (x *Complex) GetContact() isComplex_Contact {
+	if x != nil {
+		return x.Contact
+	}
+	return nil
+}
+
+func (x *Complex) GetEmail() string {
+	if x != nil {
+		if x, ok := x.Contact.(*Complex_Email); ok {
+			return x.Email
+		}
+	}
+	return ""
+}
+
+func (x *Complex) GetPhone() string {
+	if x != nil {
+		if x, ok := x.Contact.(*Complex_Phone); ok {
+			return x.Phone
+		}
+	}
+	return ""
+}
+
+func (x *Complex) GetStatus() Complex_Status {
+	if x != nil {
+		return x.Status
+	}
+	return Complex_STATUS_UNSPECIFIED
+}
+
+func (x *Complex) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Complex) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+func (x *Complex) GetNickname() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Nickname
+	}
+	return nil
+}
+
+func (x *Complex) GetEnabled() *wrapperspb.BoolValue {
+	if x != nil {
+		return x.Enabled
+	}
+	return nil
+}
+
+func (x *Complex) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *Complex) GetExtra() *structpb.Struct {
+	if x != nil {
+		return x.Extra
+	}
+	return nil
+}
+
+func (x *Complex) GetChild() *Child {
+	if x != nil {
+		return x.Child
+	}
+	return nil
+}
+
+func (x *Complex) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Complex) GetAge() int32 {
+	if x != nil && x.Age != nil {
+		return *x.Age
+	}
+	return 0
+}
+
+func (x *Complex) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type isComplex_Contact interface {
+	isComplex_Contact()
+}
+
+type Complex_Email struct {
+	Email string `protobuf:"bytes,6,opt,name=email,proto3,oneof"`
+}
+
+type Complex_Phone struct {
+	Phone string `protobuf:"bytes,7,opt,name=phone,proto3,oneof"`
+}
+
+func (*Complex_Email) isComplex_Contact() {}
+
+func (*Complex_Phone) isComplex_Contact() {}
+
+type Child struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Tags          []string               `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Child) Reset() {
+	*x = Child{}
+	mi := &file_complex_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Child) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Child) ProtoMessage() {}
+
+func (x *Child) ProtoReflect() protoreflect.Message {
+	mi := &file_complex_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Child.ProtoReflect.Descriptor instead.
+func (*Child) Descriptor() ([]byte, []int) {
+	return file_complex_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Child) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Child) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+var File_complex_proto protoreflect.FileDescriptor
+
+const file_complex_proto_rawDesc = "" +
+	"\n" +
+	"\rcomplex.proto\x12\btestdata\x1a\x1egoogle/protobuf/duration.proto\x1a google/protobuf/field_mask.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/wrappers.proto\"\x8d\a\n" +
+	"\aComplex\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
+	"\tuser_name\x18\x02 \x01(\tR\buserName\x12\x18\n" +
+	"\anumbers\x18\x03 \x03(\x05R\anumbers\x125\n" +
+	"\x06labels\x18\x04 \x03(\v2\x1d.testdata.Complex.LabelsEntryR\x06labels\x122\n" +
+	"\x05codes\x18\x05 \x03(\v2\x1c.testdata.Complex.CodesEntryR\x05codes\x12\x16\n" +
+	"\x05email\x18\x06 \x01(\tH\x00R\x05email\x12\x16\n" +
+	"\x05phone\x18\a \x01(\tH\x00R\x05phone\x120\n" +
+	"\x06status\x18\b \x01(\x0e2\x18.testdata.Complex.StatusR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12+\n" +
+	"\x03ttl\x18\n" +
+	" \x01(\v2\x19.google.protobuf.DurationR\x03ttl\x128\n" +
+	"\bnickname\x18\v \x01(\v2\x1c.google.protobuf.StringValueR\bnickname\x124\n" +
+	"\aenabled\x18\f \x01(\v2\x1a.google.protobuf.BoolValueR\aenabled\x12;\n" +
+	"\vupdate_mask\x18\r \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12-\n" +
+	"\x05extra\x18\x0e \x01(\v2\x17.google.protobuf.StructR\x05extra\x12%\n" +
+	"\x05child\x18\x0f \x01(\v2\x0f.testdata.ChildR\x05child\x12\x12\n" +
+	"\x04data\x18\x10 \x01(\fR\x04data\x12\x15\n" +
+	"\x03age\x18\x11 \x01(\x05H\x01R\x03age\x88\x01\x01\x12\x14\n" +
+	"\x05score\x18\x12 \x01(\x01R\x05score\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a8\n" +
+	"\n" +
+	"CodesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x05R\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\":\n" +
+	"\x06Status\x12\x16\n" +
+	"\x12STATUS_UNSPECIFIED\x10\x00\x12\n" +
+	"\n" +
+	"\x06ACTIVE\x10\x01\x12\f\n" +
+	"\bDISABLED\x10\x02B\t\n" +
+	"\acontactB\x06\n" +
+	"\x04_age\"/\n" +
+	"\x05Child\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04tags\x18\x02 \x03(\tR\x04tagsB,Z*kratos_c/internal/testdata/complex;complexb\x06proto3"
+
+var (
+	file_complex_proto_rawDescOnce sync.Once
+	file_complex_proto_rawDescData []byte
+)
+
+func file_complex_proto_rawDescGZIP() []byte {
+	file_complex_proto_rawDescOnce.Do(func() {
+		file_complex_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_complex_proto_rawDesc), len(file_complex_proto_rawDesc)))
+	})
+	return file_complex_proto_rawDescData
+}
+
+var file_complex_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_complex_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_complex_proto_goTypes = []any{
+	(Complex_Status)(0),            // 0: testdata.Complex.Status
+	(*Complex)(nil),                // 1: testdata.Complex
+	(*Child)(nil),                  // 2: testdata.Child
+	nil,                            // 3: testdata.Complex.LabelsEntry
+	nil,                            // 4: testdata.Complex.CodesEntry
+	(*timestamppb.Timestamp)(nil),  // 5: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),    // 6: google.protobuf.Duration
+	(*wrapperspb.StringValue)(nil), // 7: google.protobuf.StringValue
+	(*wrapperspb.BoolValue)(nil),   // 8: google.protobuf.BoolValue
+	(*fieldmaskpb.FieldMask)(nil),  // 9: google.protobuf.FieldMask
+	(*structpb.Struct)(nil),        // 10: google.protobuf.Struct
+}
+var file_complex_proto_depIdxs = []int32{
+	3,  // 0: testdata.Complex.labels:type_name -> testdata.Complex.LabelsEntry
+	4,  // 1: testdata.Complex.codes:type_name -> testdata.Complex.CodesEntry
+	0,  // 2: testdata.Complex.status:type_name -> testdata.Complex.Status
+	5,  // 3: testdata.Complex.created_at:type_name -> google.protobuf.Timestamp
+	6,  // 4: testdata.Complex.ttl:type_name -> google.protobuf.Duration
+	7,  // 5: testdata.Complex.nickname:type_name -> google.protobuf.StringValue
+	8,  // 6: testdata.Complex.enabled:type_name -> google.protobuf.BoolValue
+	9,  // 7: testdata.Complex.update_mask:type_name -> google.protobuf.FieldMask
+	10, // 8: testdata.Complex.extra:type_name -> google.protobuf.Struct
+	2,  // 9: testdata.Complex.child:type_name -> testdata.Child
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_complex_proto_init() }
+func file_complex_proto_init() {
+	if File_complex_proto != nil {
+		return
+	}
+	file_complex_proto_msgTypes[0].OneofWrappers = []any{
+		(*Complex_Email)(nil),
+		(*Complex_Phone)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_complex_proto_rawDesc), len(file_complex_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_complex_proto_goTypes,
+		DependencyIndexes: file_complex_proto_depIdxs,
+		EnumInfos:         file_complex_proto_enumTypes,
+		MessageInfos:      file_complex_proto_msgTypes,
+	}.Build()
+	File_complex_proto = out.File
+	file_complex_proto_goTypes = nil
+	file_complex_proto_depIdxs = nil
+}
@@ -0,0 +1,93 @@
+package window
+
+import (
+	"sync"
+	"time"
+)
+
+// Bucket 保存一个时间片内的采样结果
+type Bucket struct {
+	// Sum 是时间片内所有采样值之和
+	Sum float64
+	// Count 是时间片内的采样次数
+	Count int64
+}
+
+// Rolling 是按时间滚动的环形窗口, 窗口由 size 个长度为 bucketDuration 的时间片组成,
+// 过期的时间片在下一次访问时被清空.
+type Rolling struct {
+	mu             sync.Mutex
+	buckets        []Bucket
+	bucketDuration time.Duration
+	offset         int
+	lastAppendTime time.Time
+}
+
+// NewRolling new a rolling window with size buckets.
+func NewRolling(size int, bucketDuration time.Duration) *Rolling {
+	if size <= 0 {
+		panic("window: size must be greater than 0")
+	}
+	if bucketDuration <= 0 {
+		panic("window: bucket duration must be greater than 0")
+	}
+	return &Rolling{
+		buckets:        make([]Bucket, size),
+		bucketDuration: bucketDuration,
+		lastAppendTime: time.Now(),
+	}
+}
+
+// BucketDuration returns the duration of one bucket.
+func (r *Rolling) BucketDuration() time.Duration {
+	return r.bucketDuration
+}
+
+// Add 将采样值累加到当前时间片.
+func (r *Rolling) Add(v float64) {
+	r.mu.Lock()
+	r.advance()
+	b := &r.buckets[r.offset]
+	b.Sum += v
+	b.Count++
+	r.mu.Unlock()
+}
+
+// Range 按时间从旧到新遍历窗口内的时间片, skipCurrent 为 true 时不包括尚未结束的当前时间片.
+func (r *Rolling) Range(skipCurrent bool, f func(b Bucket)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.advance()
+	size := len(r.buckets)
+	n := size
+	if skipCurrent {
+		n--
+	}
+	for i := 1; i <= n; i++ {
+		f(r.buckets[(r.offset+i)%size])
+	}
+}
+
+// Sum 返回窗口内所有时间片的合计.
+func (r *Rolling) Sum() (sum float64, count int64) {
+	r.Range(false, func(b Bucket) {
+		sum += b.Sum
+		count += b.Count
+	})
+	return
+}
+
+// advance 根据经过的时间移动当前时间片, 调用方需要持有锁
+func (r *Rolling) advance() {
+	span := int(time.Since(r.lastAppendTime) / r.bucketDuration)
+	if span <= 0 {
+		return
+	}
+	size := len(r.buckets)
+	for i := 1; i <= span && i <= size; i++ {
+		r.buckets[(r.offset+i)%size] = Bucket{}
+	}
+	r.offset = (r.offset + span) % size
+	// 对齐到时间片边界, 避免误差累积
+	r.lastAppendTime = r.lastAppendTime.Add(time.Duration(span) * r.bucketDuration)
+}
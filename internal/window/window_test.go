@@ -0,0 +1,95 @@
+package window
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// elapse 模拟时间经过, 不需要真正 sleep
+func elapse(r *Rolling, d time.Duration) {
+	r.mu.Lock()
+	r.lastAppendTime = r.lastAppendTime.Add(-d)
+	r.mu.Unlock()
+}
+
+func sums(r *Rolling, skipCurrent bool) []float64 {
+	var s []float64
+	r.Range(skipCurrent, func(b Bucket) {
+		s = append(s, b.Sum)
+	})
+	return s
+}
+
+func TestRollingAdd(t *testing.T) {
+	r := NewRolling(3, time.Second)
+	r.Add(1)
+	r.Add(2)
+	if sum, count := r.Sum(); sum != 3 || count != 2 {
+		t.Errorf("got sum %v count %d, want 3 and 2", sum, count)
+	}
+	if got, want := sums(r, false), []float64{0, 0, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got buckets %v, want %v", got, want)
+	}
+	if got, want := sums(r, true), []float64{0, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("skip current: got buckets %v, want %v", got, want)
+	}
+}
+
+func TestRollingAdvance(t *testing.T) {
+	r := NewRolling(3, time.Second)
+	r.Add(1)
+	elapse(r, time.Second)
+	r.Add(2)
+	elapse(r, time.Second)
+	r.Add(3)
+	// 从旧到新
+	if got, want := sums(r, false), []float64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got buckets %v, want %v", got, want)
+	}
+	if got, want := sums(r, true), []float64{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("skip current: got buckets %v, want %v", got, want)
+	}
+
+	// 最旧的时间片过期后被清空
+	elapse(r, time.Second)
+	if got, want := sums(r, false), []float64{2, 3, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after one bucket: got buckets %v, want %v", got, want)
+	}
+
+	// 经过整个窗口后全部清空
+	elapse(r, 10*time.Second)
+	if sum, count := r.Sum(); sum != 0 || count != 0 {
+		t.Errorf("after the window: got sum %v count %d, want 0", sum, count)
+	}
+}
+
+func TestRollingAlign(t *testing.T) {
+	r := NewRolling(3, time.Second)
+	start := r.lastAppendTime
+	elapse(r, 1500*time.Millisecond)
+	r.Add(1)
+	// lastAppendTime 对齐到时间片边界, 剩余的 500ms 仍计入当前时间片
+	if got, want := start.Sub(r.lastAppendTime), 500*time.Millisecond; got != want {
+		t.Errorf("got lastAppendTime offset %v, want %v", got, want)
+	}
+}
+
+func TestNewRollingInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		size     int
+		duration time.Duration
+	}{
+		{0, time.Second},
+		{1, 0},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewRolling(%d, %v) did not panic", tt.size, tt.duration)
+				}
+			}()
+			NewRolling(tt.size, tt.duration)
+		}()
+	}
+}
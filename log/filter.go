@@ -0,0 +1,101 @@
+package log
+
+// FilterOption is filter option.
+type FilterOption func(*Filter)
+
+const fuzzyStr = "***"
+
+// FilterLevel with filter level.
+func FilterLevel(level Level) FilterOption {
+	return func(opts *Filter) {
+		opts.level = level
+	}
+}
+
+// FilterKey with filter key.
+func FilterKey(key ...string) FilterOption {
+	return func(o *Filter) {
+		for _, v := range key {
+			o.key[v] = struct{}{}
+		}
+	}
+}
+
+// FilterValue with filter value.
+func FilterValue(value ...string) FilterOption {
+	return func(o *Filter) {
+		for _, v := range value {
+			o.value[v] = struct{}{}
+		}
+	}
+}
+
+// FilterFunc with filter func.
+func FilterFunc(f func(level Level, keyvals ...any) bool) FilterOption {
+	return func(o *Filter) {
+		o.filter = f
+	}
+}
+
+// Filter is a logger filter.
+// 低于 level 的日志以及 filter 返回 true 的日志会被丢弃, key/value 命中的值会被替换为 ***.
+type Filter struct {
+	logger Logger
+	level  Level
+	key    map[string]struct{}
+	value  map[string]struct{}
+	filter func(level Level, keyvals ...any) bool
+}
+
+// NewFilter new a logger filter.
+func NewFilter(logger Logger, opts ...FilterOption) *Filter {
+	options := Filter{
+		logger: logger,
+		key:    make(map[string]struct{}),
+		value:  make(map[string]struct{}),
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return &options
+}
+
+// Log Print log by level and keyvals.
+func (f *Filter) Log(level Level, keyvals ...any) error {
+	if level < f.level {
+		return nil
+	}
+	// prefix kv 需要先展开 Valuer, 否则过滤器看不到真实的值
+	var prefixkv []any
+	l, ok := f.logger.(*logger)
+	if ok && len(l.prefix) > 0 {
+		prefixkv = make([]any, 0, len(l.prefix))
+		prefixkv = append(prefixkv, l.prefix...)
+		if l.hasValuer {
+			bindValues(l.ctx, prefixkv)
+		}
+	}
+	if f.filter != nil && (f.filter(level, prefixkv...) || f.filter(level, keyvals...)) {
+		return nil
+	}
+	if len(f.key) > 0 || len(f.value) > 0 {
+		for i := 0; i < len(keyvals); i += 2 {
+			v := i + 1
+			if v >= len(keyvals) {
+				continue
+			}
+			// 只比较字符串, 避免不可比较的值作为 map key 时 panic
+			if key, ok := keyvals[i].(string); ok {
+				if _, ok := f.key[key]; ok {
+					keyvals[v] = fuzzyStr
+				}
+			}
+			if value, ok := keyvals[v].(string); ok {
+				if _, ok := f.value[value]; ok {
+					keyvals[v] = fuzzyStr
+				}
+			}
+		}
+	}
+	return f.logger.Log(level, keyvals...)
+}
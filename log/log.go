@@ -1,9 +1,14 @@
 package log
 
-import "context"
+import (
+	"context"
+	"log"
+)
 
-var DefaultLogger =
+// DefaultLogger is default logger.
+var DefaultLogger = NewStdLogger(log.Writer())
 
+// Logger is a logger interface.
 type Logger interface {
 	Log(level Level, keyVals ...any) error
 }
@@ -16,16 +21,16 @@ type logger struct {
 }
 
 func (c *logger) Log(level Level, keyVals ...any) error {
-	kvs := make([]any, 0, len(c.prefix) + len(keyVals))
-	kvs = append(kvs, c.prefix)
+	kvs := make([]any, 0, len(c.prefix)+len(keyVals))
+	kvs = append(kvs, c.prefix...)
 	if c.hasValuer {
 		bindValues(c.ctx, kvs)
 	}
-	kvs = append(kvs, keyVals)
+	kvs = append(kvs, keyVals...)
 	return c.logger.Log(level, kvs...)
 }
 
-
+// With with logger fields.
 func With(l Logger, kv ...any) Logger {
 	c, ok := l.(*logger)
 	if !ok {
@@ -42,8 +47,8 @@ func With(l Logger, kv ...any) Logger {
 	}
 }
 
-
-
+// WithContext returns a shallow copy of l with its context changed
+// to ctx. The provided ctx must be non-nil.
 func WithContext(ctx context.Context, l Logger) Logger {
 	switch v := l.(type) {
 	default:
@@ -35,6 +35,7 @@ func (l *stdLogger) Log(level Level, keyVals ...any) error {
 		keyVals = append(keyVals, "KEYVALS UNPAIRED")
 	}
 	buf := l.pool.Get().(*bytes.Buffer)
+	buf.Reset()
 	defer l.pool.Put(buf)
 	// 先输入级别
 	buf.WriteString(level.String())
@@ -0,0 +1,23 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStdLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := NewStdLogger(buf)
+
+	if err := logger.Log(LevelInfo, "msg", "one"); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.Log(LevelWarn, "msg", "two", "unpaired"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "INFO msg=one\nWARN msg=two unpaired=KEYVALS UNPAIRED\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
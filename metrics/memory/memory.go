@@ -0,0 +1,306 @@
+package memory
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"kratos_c/metrics"
+)
+
+// DefaultBuckets 与 Prometheus 客户端默认的直方图分桶一致, 单位为秒.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+const (
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+)
+
+// Registry 是进程内的指标注册表, 可以按 Prometheus 文本格式输出所有指标.
+// 标签值的数量与注册时声明的标签不一致时(例如带标签的指标没有调用 With)忽略这次更新, 不影响业务请求.
+type Registry struct {
+	mu       sync.RWMutex
+	families map[string]*family
+}
+
+// New new an in-process metrics registry.
+func New() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// NewCounter 注册一个计数器, 同名指标重复注册时返回已有的指标.
+func (r *Registry) NewCounter(name, help string, labels ...string) metrics.Counter {
+	return &counter{f: r.register(name, help, typeCounter, labels, nil)}
+}
+
+// NewGauge 注册一个仪表盘.
+func (r *Registry) NewGauge(name, help string, labels ...string) metrics.Gauge {
+	return &gauge{f: r.register(name, help, typeGauge, labels, nil)}
+}
+
+// NewHistogram 注册一个直方图, buckets 为空时使用 DefaultBuckets.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) metrics.Observer {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &histogram{f: r.register(name, help, typeHistogram, labels, buckets)}
+}
+
+func (r *Registry) register(name, help, typ string, labels []string, buckets []float64) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.families[name]; ok {
+		if f.typ != typ || strings.Join(f.labels, ",") != strings.Join(labels, ",") {
+			panic(fmt.Sprintf("metrics: %s already registered as %s with labels %v", name, f.typ, f.labels))
+		}
+		return f
+	}
+	f := &family{
+		name:    name,
+		help:    help,
+		typ:     typ,
+		labels:  append([]string(nil), labels...),
+		buckets: buckets,
+		series:  make(map[string]*series),
+	}
+	r.families[name] = f
+	return f
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	families := make([]*family, 0, len(names))
+	sort.Strings(names)
+	for _, name := range names {
+		families = append(families, r.families[name])
+	}
+	r.mu.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	cw := &countWriter{w: bw}
+	for _, f := range families {
+		f.writeTo(cw)
+	}
+	if cw.err == nil {
+		cw.err = bw.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// ServeHTTP 以 Prometheus 文本格式响应指标抓取请求.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = r.WriteTo(w)
+}
+
+type family struct {
+	name    string
+	help    string
+	typ     string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// series 是一组标签值对应的时间序列
+type series struct {
+	lvs    []string
+	value  float64
+	counts []uint64 // 直方图每个分桶的计数, 不累加
+	count  uint64
+}
+
+// get 返回标签值对应的序列, 标签值数量不对时返回 nil, 调用方需要持有 f.mu
+func (f *family) get(lvs []string) *series {
+	if len(lvs) != len(f.labels) {
+		return nil
+	}
+	key := strings.Join(lvs, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{lvs: append([]string(nil), lvs...)}
+		if f.typ == typeHistogram {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+func (f *family) update(lvs []string, fn func(s *series)) {
+	f.mu.Lock()
+	if s := f.get(lvs); s != nil {
+		fn(s)
+	}
+	f.mu.Unlock()
+}
+
+func (f *family) writeTo(w *countWriter) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.help != "" {
+		w.printf("# HELP %s %s\n", f.name, escapeHelp(f.help))
+	}
+	w.printf("# TYPE %s %s\n", f.name, f.typ)
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s := f.series[key]
+		if f.typ != typeHistogram {
+			w.printf("%s%s %s\n", f.name, labelPairs(f.labels, s.lvs, "", ""), formatFloat(s.value))
+			continue
+		}
+		var cumulative uint64
+		for i, upper := range f.buckets {
+			cumulative += s.counts[i]
+			w.printf("%s_bucket%s %d\n", f.name, labelPairs(f.labels, s.lvs, "le", formatFloat(upper)), cumulative)
+		}
+		w.printf("%s_bucket%s %d\n", f.name, labelPairs(f.labels, s.lvs, "le", "+Inf"), s.count)
+		w.printf("%s_sum%s %s\n", f.name, labelPairs(f.labels, s.lvs, "", ""), formatFloat(s.value))
+		w.printf("%s_count%s %d\n", f.name, labelPairs(f.labels, s.lvs, "", ""), s.count)
+	}
+}
+
+type counter struct {
+	f   *family
+	lvs []string
+}
+
+func (c *counter) With(lvs ...string) metrics.Counter {
+	return &counter{f: c.f, lvs: lvs}
+}
+
+func (c *counter) Inc() {
+	c.Add(1)
+}
+
+func (c *counter) Add(delta float64) {
+	if delta < 0 {
+		panic("metrics: counter cannot decrease in value")
+	}
+	c.f.update(c.lvs, func(s *series) { s.value += delta })
+}
+
+type gauge struct {
+	f   *family
+	lvs []string
+}
+
+func (g *gauge) With(lvs ...string) metrics.Gauge {
+	return &gauge{f: g.f, lvs: lvs}
+}
+
+func (g *gauge) Set(value float64) {
+	g.f.update(g.lvs, func(s *series) { s.value = value })
+}
+
+func (g *gauge) Add(delta float64) {
+	g.f.update(g.lvs, func(s *series) { s.value += delta })
+}
+
+func (g *gauge) Sub(delta float64) {
+	g.Add(-delta)
+}
+
+type histogram struct {
+	f   *family
+	lvs []string
+}
+
+func (h *histogram) With(lvs ...string) metrics.Observer {
+	return &histogram{f: h.f, lvs: lvs}
+}
+
+func (h *histogram) Observe(value float64) {
+	i := sort.SearchFloat64s(h.f.buckets, value)
+	h.f.update(h.lvs, func(s *series) {
+		if i < len(s.counts) {
+			s.counts[i]++
+		}
+		s.count++
+		s.value += value
+	})
+}
+
+func labelPairs(labels, lvs []string, extraKey, extraValue string) string {
+	if len(labels) == 0 && extraKey == "" {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, label := range labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(label)
+		b.WriteString(`="`)
+		b.WriteString(escapeLabelValue(lvs[i]))
+		b.WriteByte('"')
+	}
+	if extraKey != "" {
+		if len(labels) > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(extraKey)
+		b.WriteString(`="`)
+		b.WriteString(extraValue)
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var (
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabelValue(s string) string { return labelValueEscaper.Replace(s) }
+
+func escapeHelp(s string) string { return helpEscaper.Replace(s) }
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+type countWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (w *countWriter) printf(format string, a ...any) {
+	if w.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(w.w, format, a...)
+	w.n += int64(n)
+	w.err = err
+}
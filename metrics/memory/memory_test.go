@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func output(t *testing.T, r *Registry) string {
+	t.Helper()
+	var b strings.Builder
+	n, err := r.WriteTo(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int(n) != b.Len() {
+		t.Errorf("WriteTo returned %d bytes, wrote %d", n, b.Len())
+	}
+	return b.String()
+}
+
+func TestCounter(t *testing.T) {
+	r := New()
+	c := r.NewCounter("requests_total", "Total requests.", "kind", "code")
+	c.With("grpc", "200").Inc()
+	c.With("grpc", "200").Add(2)
+	c.With("http", "500").Inc()
+	want := `# HELP requests_total Total requests.
+# TYPE requests_total counter
+requests_total{kind="grpc",code="200"} 3
+requests_total{kind="http",code="500"} 1
+`
+	if got := output(t, r); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGauge(t *testing.T) {
+	r := New()
+	g := r.NewGauge("inflight", "")
+	g.Set(5)
+	g.Add(2)
+	g.Sub(3)
+	want := `# TYPE inflight gauge
+inflight 4
+`
+	if got := output(t, r); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	r := New()
+	h := r.NewHistogram("seconds", "Latency.", []float64{1, 0.1}, "op")
+	for _, v := range []float64{0.05, 0.1, 0.5, 2} {
+		h.With("/a").Observe(v)
+	}
+	want := `# HELP seconds Latency.
+# TYPE seconds histogram
+seconds_bucket{op="/a",le="0.1"} 2
+seconds_bucket{op="/a",le="1"} 3
+seconds_bucket{op="/a",le="+Inf"} 4
+seconds_sum{op="/a"} 2.65
+seconds_count{op="/a"} 4
+`
+	if got := output(t, r); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLabelMismatch(t *testing.T) {
+	r := New()
+	c := r.NewCounter("requests_total", "", "kind", "code")
+	g := r.NewGauge("inflight", "", "kind")
+	h := r.NewHistogram("seconds", "", nil, "kind")
+	// 没有调用 With 或者标签值数量不对时忽略, 不能 panic
+	c.Inc()
+	c.Add(1)
+	c.With("grpc").Inc()
+	c.With("grpc", "200", "extra").Inc()
+	g.Set(1)
+	g.With("a", "b").Add(1)
+	h.Observe(1)
+	h.With().Observe(1)
+	want := `# TYPE inflight gauge
+# TYPE requests_total counter
+# TYPE seconds histogram
+`
+	if got := output(t, r); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	r := New()
+	r.NewCounter("requests_total", "", "kind").With("grpc").Inc()
+	r.NewCounter("requests_total", "", "kind").With("grpc").Inc()
+	if got := output(t, r); !strings.Contains(got, `requests_total{kind="grpc"} 2`) {
+		t.Errorf("got\n%s\nwant both counters to share the series", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a gauge with a counter name did not panic")
+		}
+	}()
+	r.NewGauge("requests_total", "", "kind")
+}
+
+func TestEscape(t *testing.T) {
+	r := New()
+	r.NewCounter("escaped_total", "line\none \\", "path").With("a\"b\\c\nd").Inc()
+	want := `# HELP escaped_total line\none \\
+# TYPE escaped_total counter
+escaped_total{path="a\"b\\c\nd"} 1
+`
+	if got := output(t, r); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	r := New()
+	r.NewCounter("requests_total", "").Inc()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("got content type %q", ct)
+	}
+	if got := w.Body.String(); !strings.Contains(got, "requests_total 1\n") {
+		t.Errorf("got body\n%s", got)
+	}
+}
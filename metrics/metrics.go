@@ -0,0 +1,23 @@
+package metrics
+
+// Counter is metrics counter.
+type Counter interface {
+	// With 按声明标签的顺序绑定标签值
+	With(lvs ...string) Counter
+	Inc()
+	Add(delta float64)
+}
+
+// Gauge is metrics gauge.
+type Gauge interface {
+	With(lvs ...string) Gauge
+	Set(value float64)
+	Add(delta float64)
+	Sub(delta float64)
+}
+
+// Observer is metrics observer.
+type Observer interface {
+	With(lvs ...string) Observer
+	Observe(float64)
+}
@@ -0,0 +1,116 @@
+// Package otel adapts OpenTelemetry instruments to the metrics interfaces.
+package otel
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"kratos_c/metrics"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	_ metrics.Counter  = (*counter)(nil)
+	_ metrics.Gauge    = (*gauge)(nil)
+	_ metrics.Observer = (*observer)(nil)
+)
+
+type counter struct {
+	cv     metric.Float64Counter
+	labels []string
+	lvs    []string
+}
+
+// NewCounter new an OpenTelemetry counter, labels 是 With 中标签值对应的属性名.
+func NewCounter(cv metric.Float64Counter, labels ...string) metrics.Counter {
+	return &counter{cv: cv, labels: labels}
+}
+
+func (c *counter) With(lvs ...string) metrics.Counter {
+	return &counter{cv: c.cv, labels: c.labels, lvs: lvs}
+}
+
+func (c *counter) Inc() {
+	c.Add(1)
+}
+
+func (c *counter) Add(delta float64) {
+	c.cv.Add(context.Background(), delta, metric.WithAttributes(attributes(c.labels, c.lvs)...))
+}
+
+// gaugeValues 保存每组标签值的当前值, OpenTelemetry 的同步 Gauge 只能记录绝对值
+type gaugeValues struct {
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+type gauge struct {
+	gv     metric.Float64Gauge
+	labels []string
+	lvs    []string
+	state  *gaugeValues
+}
+
+// NewGauge new an OpenTelemetry gauge.
+func NewGauge(gv metric.Float64Gauge, labels ...string) metrics.Gauge {
+	return &gauge{gv: gv, labels: labels, state: &gaugeValues{values: make(map[string]float64)}}
+}
+
+func (g *gauge) With(lvs ...string) metrics.Gauge {
+	return &gauge{gv: g.gv, labels: g.labels, lvs: lvs, state: g.state}
+}
+
+func (g *gauge) Set(value float64) {
+	g.update(func(float64) float64 { return value })
+}
+
+func (g *gauge) Add(delta float64) {
+	g.update(func(v float64) float64 { return v + delta })
+}
+
+func (g *gauge) Sub(delta float64) {
+	g.update(func(v float64) float64 { return v - delta })
+}
+
+func (g *gauge) update(fn func(float64) float64) {
+	key := strings.Join(g.lvs, "\xff")
+	g.state.mu.Lock()
+	value := fn(g.state.values[key])
+	g.state.values[key] = value
+	// 在锁内记录, 保证并发更新时上报的值与保存的值顺序一致
+	g.gv.Record(context.Background(), value, metric.WithAttributes(attributes(g.labels, g.lvs)...))
+	g.state.mu.Unlock()
+}
+
+type observer struct {
+	hv     metric.Float64Histogram
+	labels []string
+	lvs    []string
+}
+
+// NewHistogram new an OpenTelemetry histogram observer.
+func NewHistogram(hv metric.Float64Histogram, labels ...string) metrics.Observer {
+	return &observer{hv: hv, labels: labels}
+}
+
+func (o *observer) With(lvs ...string) metrics.Observer {
+	return &observer{hv: o.hv, labels: o.labels, lvs: lvs}
+}
+
+func (o *observer) Observe(value float64) {
+	o.hv.Record(context.Background(), value, metric.WithAttributes(attributes(o.labels, o.lvs)...))
+}
+
+func attributes(labels, lvs []string) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(labels))
+	for i, label := range labels {
+		if i >= len(lvs) {
+			break
+		}
+		attrs = append(attrs, attribute.String(label, lvs[i]))
+	}
+	return attrs
+}
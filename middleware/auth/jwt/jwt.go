@@ -0,0 +1,203 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"kratos_c/errors"
+	"kratos_c/middleware"
+	"kratos_c/transport"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type authKey struct{}
+
+const (
+	// bearerWord the bearer key word for authorization
+	bearerWord string = "Bearer"
+
+	// bearerFormat authorization token format
+	bearerFormat string = "Bearer %s"
+
+	// authorizationKey holds the key used to store the JWT Token in the request tokenHeader.
+	authorizationKey string = "Authorization"
+
+	// reason holds the error reason.
+	reason string = "UNAUTHORIZED"
+)
+
+var (
+	ErrMissingJwtToken        = errors.Unauthorized(reason, "JWT token is missing")
+	ErrMissingKeyFunc         = errors.Unauthorized(reason, "keyFunc is missing")
+	ErrTokenInvalid           = errors.Unauthorized(reason, "Token is invalid")
+	ErrTokenExpired           = errors.Unauthorized(reason, "JWT token has expired")
+	ErrTokenNotValidYet       = errors.Unauthorized(reason, "JWT token is not valid yet")
+	ErrTokenParseFail         = errors.Unauthorized(reason, "Fail to parse JWT token ")
+	ErrUnSupportSigningMethod = errors.Unauthorized(reason, "Wrong signing method")
+	ErrWrongContext           = errors.Unauthorized(reason, "Wrong context for middleware")
+	ErrNeedTokenProvider      = errors.Unauthorized(reason, "Token provider is missing")
+	ErrSignToken              = errors.Unauthorized(reason, "Can not sign token.Is the key correct?")
+	ErrGetKey                 = errors.Unauthorized(reason, "Can not get key while signing token")
+)
+
+// Option is jwt option.
+type Option func(*options)
+
+// options is jwt options.
+type options struct {
+	signingMethod jwt.SigningMethod
+	claims        func() jwt.Claims
+	tokenHeader   map[string]any
+	parserOptions []jwt.ParserOption
+}
+
+// WithSigningMethod with signing method option.
+func WithSigningMethod(method jwt.SigningMethod) Option {
+	return func(o *options) {
+		o.signingMethod = method
+	}
+}
+
+// WithClaims with customer claim
+// If you use it in Server, f needs to return a new jwt.Claims object each time to avoid concurrent write problems
+// If you use it in Client, f only needs to return a single object to provide performance
+func WithClaims(f func() jwt.Claims) Option {
+	return func(o *options) {
+		o.claims = f
+	}
+}
+
+// WithTokenHeader withe customer tokenHeader for client side
+func WithTokenHeader(header map[string]any) Option {
+	return func(o *options) {
+		o.tokenHeader = header
+	}
+}
+
+// WithAudience 要求 token 的 aud 包含 aud, 只用于服务端.
+func WithAudience(aud ...string) Option {
+	return func(o *options) {
+		o.parserOptions = append(o.parserOptions, jwt.WithAudience(aud...))
+	}
+}
+
+// WithIssuer 要求 token 的 iss 等于 iss, 只用于服务端.
+func WithIssuer(iss string) Option {
+	return func(o *options) {
+		o.parserOptions = append(o.parserOptions, jwt.WithIssuer(iss))
+	}
+}
+
+// WithExpirationRequired 要求 token 必须包含 exp, 只用于服务端. 没有这个选项时只在 exp 存在时校验过期.
+func WithExpirationRequired() Option {
+	return func(o *options) {
+		o.parserOptions = append(o.parserOptions, jwt.WithExpirationRequired())
+	}
+}
+
+// Server is a server auth middleware. Check the token and extract the info from token.
+func Server(keyFunc jwt.Keyfunc, opts ...Option) middleware.Middleware {
+	o := &options{
+		signingMethod: jwt.SigningMethodHS256,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	parser := jwt.NewParser(append([]jwt.ParserOption{jwt.WithValidMethods([]string{o.signingMethod.Alg()})}, o.parserOptions...)...)
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			header, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return nil, ErrWrongContext
+			}
+			if keyFunc == nil {
+				return nil, ErrMissingKeyFunc
+			}
+			auths := strings.SplitN(header.RequestHeader().Get(authorizationKey), " ", 2)
+			if len(auths) != 2 || !strings.EqualFold(auths[0], bearerWord) {
+				return nil, ErrMissingJwtToken
+			}
+			jwtToken := auths[1]
+			var (
+				tokenInfo *jwt.Token
+				err       error
+			)
+			if o.claims != nil {
+				tokenInfo, err = parser.ParseWithClaims(jwtToken, o.claims(), keyFunc)
+			} else {
+				tokenInfo, err = parser.Parse(jwtToken, keyFunc)
+			}
+			if err != nil {
+				switch {
+				case errors.Is(err, jwt.ErrTokenMalformed) || errors.Is(err, jwt.ErrTokenUnverifiable):
+					return nil, ErrTokenInvalid.WithCause(err)
+				case errors.Is(err, jwt.ErrTokenSignatureInvalid) && strings.Contains(err.Error(), "signing method"):
+					return nil, ErrUnSupportSigningMethod.WithCause(err)
+				case errors.Is(err, jwt.ErrTokenExpired):
+					return nil, ErrTokenExpired.WithCause(err)
+				case errors.Is(err, jwt.ErrTokenNotValidYet):
+					return nil, ErrTokenNotValidYet.WithCause(err)
+				case errors.Is(err, jwt.ErrTokenInvalidAudience) || errors.Is(err, jwt.ErrTokenInvalidIssuer) ||
+					errors.Is(err, jwt.ErrTokenRequiredClaimMissing) || errors.Is(err, jwt.ErrTokenSignatureInvalid):
+					return nil, ErrTokenInvalid.WithCause(err)
+				default:
+					return nil, ErrTokenParseFail.WithCause(err)
+				}
+			}
+			if !tokenInfo.Valid {
+				return nil, ErrTokenInvalid
+			}
+			ctx = NewContext(ctx, tokenInfo.Claims)
+			return handler(ctx, req)
+		}
+	}
+}
+
+// Client is a client jwt middleware.
+func Client(keyProvider jwt.Keyfunc, opts ...Option) middleware.Middleware {
+	claims := jwt.RegisteredClaims{}
+	o := &options{
+		signingMethod: jwt.SigningMethodHS256,
+		claims:        func() jwt.Claims { return claims },
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			if keyProvider == nil {
+				return nil, ErrNeedTokenProvider
+			}
+			token := jwt.NewWithClaims(o.signingMethod, o.claims())
+			for k, v := range o.tokenHeader {
+				token.Header[k] = v
+			}
+			key, err := keyProvider(token)
+			if err != nil {
+				return nil, ErrGetKey.WithCause(err)
+			}
+			tokenStr, err := token.SignedString(key)
+			if err != nil {
+				return nil, ErrSignToken.WithCause(err)
+			}
+			if clientContext, ok := transport.FromClientContext(ctx); ok {
+				clientContext.RequestHeader().Set(authorizationKey, fmt.Sprintf(bearerFormat, tokenStr))
+				return handler(ctx, req)
+			}
+			return nil, ErrWrongContext
+		}
+	}
+}
+
+// NewContext put auth info into context
+func NewContext(ctx context.Context, info jwt.Claims) context.Context {
+	return context.WithValue(ctx, authKey{}, info)
+}
+
+// FromContext extract auth info from context
+func FromContext(ctx context.Context) (token jwt.Claims, ok bool) {
+	token, ok = ctx.Value(authKey{}).(jwt.Claims)
+	return
+}
@@ -0,0 +1,335 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"kratos_c/errors"
+	"kratos_c/middleware"
+	"kratos_c/transport"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type headerCarrier map[string]string
+
+func (h headerCarrier) Get(key string) string      { return h[key] }
+func (h headerCarrier) Set(key, value string)      { h[key] = value }
+func (h headerCarrier) Add(key, value string)      { h[key] = value }
+func (h headerCarrier) Values(key string) []string { return []string{h[key]} }
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+type testTransport struct {
+	header headerCarrier
+}
+
+func (tr *testTransport) Kind() transport.Kind            { return transport.KindHTTP }
+func (tr *testTransport) Endpoint() string                { return "" }
+func (tr *testTransport) Operation() string               { return "/test" }
+func (tr *testTransport) RequestHeader() transport.Header { return tr.header }
+func (tr *testTransport) ReplyHeader() transport.Header   { return headerCarrier{} }
+
+// roundTrip 用 client 签发 token, 再交给 server 校验, 返回 server 拿到的 claims
+func roundTrip(client, server middleware.Middleware) (jwt.Claims, error) {
+	header := headerCarrier{}
+	_, err := client(func(context.Context, any) (any, error) { return nil, nil })(
+		transport.NewClientContext(context.Background(), &testTransport{header: header}), nil)
+	if err != nil {
+		return nil, err
+	}
+	return verify(server, header)
+}
+
+func verify(server middleware.Middleware, header headerCarrier) (jwt.Claims, error) {
+	var claims jwt.Claims
+	_, err := server(func(ctx context.Context, _ any) (any, error) {
+		claims, _ = FromContext(ctx)
+		return nil, nil
+	})(transport.NewServerContext(context.Background(), &testTransport{header: header}), nil)
+	return claims, err
+}
+
+// sign 直接签发 token, 用于构造客户端中间件无法生成的 token
+func sign(t *testing.T, method jwt.SigningMethod, key any, claims jwt.MapClaims, kid string) headerCarrier {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return headerCarrier{authorizationKey: "Bearer " + s}
+}
+
+// assertError 比较 Message, 所有 jwt 错误的 Code 和 Reason 都相同
+func assertError(t *testing.T, name string, err, want error) {
+	t.Helper()
+	switch {
+	case want == nil && err == nil:
+	case want == nil || err == nil:
+		t.Errorf("%s: got error %v, want %v", name, err, want)
+	case errors.FromError(err).Message != errors.FromError(want).Message:
+		t.Errorf("%s: got error %v, want %v", name, err, want)
+	}
+}
+
+func pemEncode(t *testing.T, pub any) []byte {
+	t.Helper()
+	b, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b})
+}
+
+func TestRoundTrip(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaFunc, err := RSA(pemEncode(t, &rsaKey.PublicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecFunc, err := ECDSA(pemEncode(t, &ecKey.PublicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := func() jwt.Claims { return jwt.MapClaims{"sub": "user"} }
+
+	tests := []struct {
+		name      string
+		method    jwt.SigningMethod
+		signKey   jwt.Keyfunc
+		verifyKey jwt.Keyfunc
+	}{
+		{"HS256", jwt.SigningMethodHS256, HMAC([]byte("secret")), HMAC([]byte("secret"))},
+		{"RS256", jwt.SigningMethodRS256, SigningKey(rsaKey), rsaFunc},
+		{"ES256", jwt.SigningMethodES256, SigningKey(ecKey), ecFunc},
+	}
+	for _, tt := range tests {
+		got, err := roundTrip(
+			Client(tt.signKey, WithSigningMethod(tt.method), WithClaims(claims)),
+			Server(tt.verifyKey, WithSigningMethod(tt.method)),
+		)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if sub, _ := got.GetSubject(); sub != "user" {
+			t.Errorf("%s: subject = %q, want user", tt.name, sub)
+		}
+	}
+
+	// 签名密钥不匹配
+	_, err = roundTrip(Client(HMAC([]byte("a"))), Server(HMAC([]byte("b"))))
+	assertError(t, "wrong secret", err, ErrTokenInvalid)
+	// 服务端只接受 HS256, token 使用 RS256
+	_, err = roundTrip(Client(SigningKey(rsaKey), WithSigningMethod(jwt.SigningMethodRS256)), Server(HMAC([]byte("secret"))))
+	assertError(t, "alg mismatch", err, ErrUnSupportSigningMethod)
+}
+
+func TestServerErrors(t *testing.T) {
+	secret := []byte("secret")
+	server := Server(HMAC(secret))
+	_, err := verify(server, headerCarrier{})
+	assertError(t, "missing token", err, ErrMissingJwtToken)
+	_, err = verify(server, headerCarrier{authorizationKey: "Basic abc"})
+	assertError(t, "wrong scheme", err, ErrMissingJwtToken)
+	_, err = verify(server, headerCarrier{authorizationKey: "Bearer abc"})
+	assertError(t, "malformed", err, ErrTokenInvalid)
+	_, err = verify(Server(nil), headerCarrier{})
+	assertError(t, "missing key func", err, ErrMissingKeyFunc)
+	_, err = server(func(context.Context, any) (any, error) { return nil, nil })(context.Background(), nil)
+	assertError(t, "no transport", err, ErrWrongContext)
+	_, err = Client(HMAC(secret))(func(context.Context, any) (any, error) { return nil, nil })(context.Background(), nil)
+	assertError(t, "client without transport", err, ErrWrongContext)
+	_, err = Client(nil)(func(context.Context, any) (any, error) { return nil, nil })(context.Background(), nil)
+	assertError(t, "client without key", err, ErrNeedTokenProvider)
+}
+
+func TestClaimsValidation(t *testing.T) {
+	secret := []byte("secret")
+	server := Server(HMAC(secret), WithAudience("api"), WithIssuer("issuer"), WithExpirationRequired())
+	now := time.Now()
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{"aud": "api", "iss": "issuer", "exp": now.Add(time.Hour).Unix()}
+	}
+	with := func(k string, v any) jwt.MapClaims {
+		c := valid()
+		if v == nil {
+			delete(c, k)
+		} else {
+			c[k] = v
+		}
+		return c
+	}
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		err    error
+	}{
+		{"valid", valid(), nil},
+		{"audience list", with("aud", []string{"other", "api"}), nil},
+		{"wrong audience", with("aud", "other"), ErrTokenInvalid},
+		{"missing audience", with("aud", nil), ErrTokenInvalid},
+		{"wrong issuer", with("iss", "other"), ErrTokenInvalid},
+		{"expired", with("exp", now.Add(-time.Hour).Unix()), ErrTokenExpired},
+		{"missing exp", with("exp", nil), ErrTokenInvalid},
+		{"not valid yet", with("nbf", now.Add(time.Hour).Unix()), ErrTokenNotValidYet},
+	}
+	for _, tt := range tests {
+		_, err := verify(server, sign(t, jwt.SigningMethodHS256, secret, tt.claims, ""))
+		assertError(t, tt.name, err, tt.err)
+	}
+
+	// 没有 WithExpirationRequired 时不要求 exp, 但存在时仍然校验
+	lenient := Server(HMAC(secret))
+	_, err := verify(lenient, sign(t, jwt.SigningMethodHS256, secret, jwt.MapClaims{}, ""))
+	assertError(t, "lenient without exp", err, nil)
+	_, err = verify(lenient, sign(t, jwt.SigningMethodHS256, secret, jwt.MapClaims{"exp": now.Add(-time.Hour).Unix()}, ""))
+	assertError(t, "lenient expired", err, ErrTokenExpired)
+}
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+func rsaJWK(kid, alg string, pub *rsa.PublicKey) map[string]string {
+	return map[string]string{"kty": "RSA", "kid": kid, "alg": alg, "use": "sig", "n": b64(pub.N.Bytes()), "e": b64(big.NewInt(int64(pub.E)).Bytes())}
+}
+
+func ecJWK(kid, alg string, pub *ecdsa.PublicKey) map[string]string {
+	return map[string]string{"kty": "EC", "kid": kid, "alg": alg, "crv": "P-256", "x": b64(pub.X.FillBytes(make([]byte, 32))), "y": b64(pub.Y.FillBytes(make([]byte, 32)))}
+}
+
+func jwks(t *testing.T, keys ...map[string]string) []byte {
+	t.Helper()
+	b, err := json.Marshal(map[string]any{"keys": keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFunc, err := JWKS(jwks(t,
+		rsaJWK("rsa", "RS256", &rsaKey.PublicKey),
+		ecJWK("ec", "ES256", &ecKey.PublicKey),
+		map[string]string{"kty": "oct", "kid": "hmac", "k": b64([]byte("secret"))},
+		// 用于加密的 key 被忽略
+		map[string]string{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := Server(keyFunc, WithSigningMethod(jwt.SigningMethodRS256))
+	ecServer := Server(keyFunc, WithSigningMethod(jwt.SigningMethodES256))
+	claims := jwt.MapClaims{"sub": "user"}
+
+	tests := []struct {
+		name   string
+		server middleware.Middleware
+		header headerCarrier
+		err    error
+	}{
+		{"rsa kid", server, sign(t, jwt.SigningMethodRS256, rsaKey, claims, "rsa"), nil},
+		{"ec kid", ecServer, sign(t, jwt.SigningMethodES256, ecKey, claims, "ec"), nil},
+		{"hmac kid", Server(keyFunc), sign(t, jwt.SigningMethodHS256, []byte("secret"), claims, "hmac"), nil},
+		{"unknown kid", server, sign(t, jwt.SigningMethodRS256, rsaKey, claims, "other"), ErrTokenInvalid},
+		{"encryption key", server, sign(t, jwt.SigningMethodRS256, rsaKey, claims, "enc"), ErrTokenInvalid},
+		// 多个 key 时必须指定 kid
+		{"no kid", server, sign(t, jwt.SigningMethodRS256, rsaKey, claims, ""), ErrTokenInvalid},
+		// kid 指向的 key 声明了不同的 alg
+		{"alg mismatch", server, sign(t, jwt.SigningMethodRS256, rsaKey, claims, "ec"), ErrTokenInvalid},
+		{"wrong key for kid", server, sign(t, jwt.SigningMethodRS256, mustRSA(t), claims, "rsa"), ErrTokenInvalid},
+	}
+	for _, tt := range tests {
+		_, err := verify(tt.server, tt.header)
+		assertError(t, tt.name, err, tt.err)
+	}
+}
+
+func mustRSA(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestJWKSFileSingleKey(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err = os.WriteFile(path, jwks(t, ecJWK("only", "", &ecKey.PublicKey)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	keyFunc, err := JWKSFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := Server(keyFunc, WithSigningMethod(jwt.SigningMethodES256))
+	// 只有一个 key 时, 没有 kid 的 token 也使用它
+	_, err = verify(server, sign(t, jwt.SigningMethodES256, ecKey, jwt.MapClaims{}, ""))
+	assertError(t, "single key without kid", err, nil)
+	_, err = verify(server, sign(t, jwt.SigningMethodES256, ecKey, jwt.MapClaims{}, "other"))
+	assertError(t, "single key with unknown kid", err, ErrTokenInvalid)
+
+	if _, err = JWKSFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("missing file should fail")
+	}
+}
+
+func TestJWKSInvalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"json":    `{`,
+		"kty":     `{"keys":[{"kty":"OKP","kid":"a"}]}`,
+		"curve":   `{"keys":[{"kty":"EC","kid":"a","crv":"P-192","x":"AQ","y":"AQ"}]}`,
+		"modulus": `{"keys":[{"kty":"RSA","kid":"a","n":"!!","e":"AQAB"}]}`,
+		"oct key": `{"keys":[{"kty":"oct","kid":"a","k":"!!"}]}`,
+	} {
+		if _, err := JWKS([]byte(data)); err == nil {
+			t.Errorf("%s: JWKS should fail", name)
+		}
+	}
+	if _, err := RSA([]byte("not pem")); err == nil {
+		t.Errorf("RSA should fail on invalid PEM")
+	}
+	if _, err := ECDSA([]byte("not pem")); err == nil {
+		t.Errorf("ECDSA should fail on invalid PEM")
+	}
+}
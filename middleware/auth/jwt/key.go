@@ -0,0 +1,173 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// HMAC 返回使用共享密钥的 key func, 服务端校验和客户端签名都可以使用.
+func HMAC(secret []byte) jwt.Keyfunc {
+	return func(*jwt.Token) (any, error) {
+		return secret, nil
+	}
+}
+
+// RSA 返回使用 RSA 公钥校验的 key func, pemBytes 为 PEM 编码的公钥或证书.
+func RSA(pemBytes []byte) (jwt.Keyfunc, error) {
+	key, err := jwt.ParseRSAPublicKeyFromPEM(pemBytes)
+	if err != nil {
+		return nil, err
+	}
+	return func(*jwt.Token) (any, error) {
+		return key, nil
+	}, nil
+}
+
+// ECDSA 返回使用 ECDSA 公钥校验的 key func, pemBytes 为 PEM 编码的公钥或证书.
+func ECDSA(pemBytes []byte) (jwt.Keyfunc, error) {
+	key, err := jwt.ParseECPublicKeyFromPEM(pemBytes)
+	if err != nil {
+		return nil, err
+	}
+	return func(*jwt.Token) (any, error) {
+		return key, nil
+	}, nil
+}
+
+// SigningKey 返回固定签名密钥的 key func, 用于客户端签名, 例如 *rsa.PrivateKey 或 *ecdsa.PrivateKey.
+func SigningKey(key any) jwt.Keyfunc {
+	return func(*jwt.Token) (any, error) {
+		return key, nil
+	}
+}
+
+// jwk is a JSON Web Key, 只解析校验签名需要的字段.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	// oct
+	K string `json:"k"`
+}
+
+// JWKSFile 从本地 JWKS 文件加载公钥, 按 token header 中的 kid 选择 key.
+// 文件只有一个 key 时, 没有 kid 的 token 也会使用它.
+func JWKSFile(path string) (jwt.Keyfunc, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return JWKS(b)
+}
+
+// JWKS 解析 JWKS 文档, 返回按 kid 选择 key 的 key func.
+func JWKS(data []byte) (jwt.Keyfunc, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	type entry struct {
+		alg string
+		key any
+	}
+	keys := make(map[string]entry, len(set.Keys))
+	var only *entry
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwks: key %q: %w", k.Kid, err)
+		}
+		e := entry{alg: k.Alg, key: key}
+		keys[k.Kid] = e
+		only = &e
+	}
+	if len(keys) != 1 {
+		only = nil
+	}
+	return func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		e, ok := keys[kid]
+		if !ok {
+			if kid != "" || only == nil {
+				return nil, fmt.Errorf("jwks: key %q not found", kid)
+			}
+			e = *only
+		}
+		if e.alg != "" && token.Method != nil && e.alg != token.Method.Alg() {
+			return nil, fmt.Errorf("jwks: key %q is for %s, token uses %s", kid, e.alg, token.Method.Alg())
+		}
+		return e.key, nil
+	}, nil
+}
+
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "oct":
+		return base64.RawURLEncoding.DecodeString(k.K)
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
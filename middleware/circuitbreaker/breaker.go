@@ -0,0 +1,148 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"kratos_c/internal/window"
+)
+
+// ErrBreakerOpen 熔断器拒绝了本次请求
+var ErrBreakerOpen = errors.New("circuitbreaker: not allowed for circuit open")
+
+// CircuitBreaker is a circuit breaker.
+type CircuitBreaker interface {
+	Allow() error
+	MarkSuccess()
+	MarkFailed()
+}
+
+const (
+	// StateOpen when circuit breaker open, request not allowed, after sleep
+	// some duration, allow one single request for testing the health, if ok
+	// then state reset to closed, if not continue the step.
+	StateOpen int32 = iota
+	// StateClosed when circuit breaker closed, request allowed, the breaker
+	// calc the succeed ratio, if request num greater request setting and
+	// ratio lower than the setting ratio, then reset state to open.
+	StateClosed
+)
+
+// SREOption is sre breaker option function.
+type SREOption func(*sreOptions)
+
+type sreOptions struct {
+	success float64
+	request int64
+	bucket  int
+	window  time.Duration
+}
+
+// WithSuccess with the K = 1 / Success value of sre breaker, default success is 0.6
+// Reducing the K will make adaptive throttling behave more aggressively,
+// Increasing the K will make adaptive throttling behave less aggressively.
+func WithSuccess(s float64) SREOption {
+	return func(c *sreOptions) {
+		c.success = s
+	}
+}
+
+// WithRequest with the minimum number of requests allowed.
+func WithRequest(r int64) SREOption {
+	return func(c *sreOptions) {
+		c.request = r
+	}
+}
+
+// WithWindow with the duration size of the statistical window, 小于等于 0 时忽略.
+func WithWindow(size time.Duration) SREOption {
+	return func(c *sreOptions) {
+		if size > 0 {
+			c.window = size
+		}
+	}
+}
+
+// WithBucket set the bucket number in a window duration.
+// If the bucket number is too large, the window will be too small to
+// hold enough samples. 小于等于 0 时忽略.
+func WithBucket(b int) SREOption {
+	return func(c *sreOptions) {
+		if b > 0 {
+			c.bucket = b
+		}
+	}
+}
+
+// SRE 实现了 Google SRE 的客户端自适应限流:
+// 拒绝概率 = max(0, (requests - K * accepts) / (requests + 1)).
+// 参考 https://sre.google/sre-book/handling-overload/
+type SRE struct {
+	stat    *window.Rolling
+	k       float64
+	request int64
+	state   atomic.Int32
+}
+
+// NewSRE return a sre breaker.
+func NewSRE(opts ...SREOption) *SRE {
+	opt := sreOptions{
+		success: 0.6,
+		request: 100,
+		bucket:  10,
+		window:  3 * time.Second,
+	}
+	for _, o := range opts {
+		o(&opt)
+	}
+	bucketDuration := max(opt.window/time.Duration(opt.bucket), 1)
+	b := &SRE{
+		stat:    window.NewRolling(opt.bucket, bucketDuration),
+		k:       1 / opt.success,
+		request: opt.request,
+	}
+	b.state.Store(StateClosed)
+	return b
+}
+
+// summary 返回窗口内的成功数与请求总数
+func (b *SRE) summary() (success float64, total int64) {
+	return b.stat.Sum()
+}
+
+// Allow request if error returns nil.
+func (b *SRE) Allow() error {
+	accepts, total := b.summary()
+	requests := b.k * accepts
+	// check overflow requests = K * success
+	if total < b.request || float64(total) < requests {
+		b.state.CompareAndSwap(StateOpen, StateClosed)
+		return nil
+	}
+	b.state.CompareAndSwap(StateClosed, StateOpen)
+	dr := math.Max(0, (float64(total)-requests)/float64(total+1))
+	if rand.Float64() < dr {
+		return ErrBreakerOpen
+	}
+	return nil
+}
+
+// MarkSuccess mark request is success.
+func (b *SRE) MarkSuccess() {
+	b.stat.Add(1)
+}
+
+// MarkFailed mark request is failed.
+func (b *SRE) MarkFailed() {
+	// NOTE: when client reject request locally, continue add counter let the
+	// drop ratio higher.
+	b.stat.Add(0)
+}
+
+// State 返回熔断器当前的状态
+func (b *SRE) State() int32 {
+	return b.state.Load()
+}
@@ -0,0 +1,120 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// mark 按顺序记录 success 个成功和 failed 个失败
+func mark(b *SRE, success, failed int) {
+	for range success {
+		b.MarkSuccess()
+	}
+	for range failed {
+		b.MarkFailed()
+	}
+}
+
+// dropRatio 统计 n 次 Allow 中被拒绝的比例, Allow 本身不计入窗口
+func dropRatio(t *testing.T, b *SRE, n int) float64 {
+	t.Helper()
+	var dropped int
+	for range n {
+		switch err := b.Allow(); {
+		case err == nil:
+		case errors.Is(err, ErrBreakerOpen):
+			dropped++
+		default:
+			t.Fatalf("got error %v, want %v", err, ErrBreakerOpen)
+		}
+	}
+	return float64(dropped) / float64(n)
+}
+
+func TestSRE(t *testing.T) {
+	tests := []struct {
+		name    string
+		success int
+		failed  int
+		state   int32
+	}{
+		// 请求数未达到 request 时总是放行
+		{"below request", 0, 99, StateClosed},
+		{"all success", 100, 0, StateClosed},
+		// K * accepts = 100 / 0.6 > 100
+		{"healthy", 70, 30, StateClosed},
+		// K * accepts = 100, 开始计算拒绝概率, 但概率为 0
+		{"threshold", 60, 40, StateOpen},
+		{"half", 30, 70, StateOpen},
+		{"all failed", 0, 100, StateOpen},
+		{"all failed many", 0, 1000, StateOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewSRE()
+			mark(b, tt.success, tt.failed)
+			total := float64(tt.success + tt.failed)
+			var want float64
+			if total >= 100 {
+				want = math.Max(0, (total-float64(tt.success)/0.6)/(total+1))
+			}
+			if got := dropRatio(t, b, 10000); math.Abs(got-want) > 0.03 {
+				t.Errorf("got drop ratio %.3f, want %.3f", got, want)
+			}
+			if got := b.State(); got != tt.state {
+				t.Errorf("got state %d, want %d", got, tt.state)
+			}
+		})
+	}
+}
+
+func TestSREOptions(t *testing.T) {
+	// K = 1 / 0.5 = 2, 一半成功时恰好不拒绝
+	b := NewSRE(WithSuccess(0.5), WithRequest(10))
+	mark(b, 5, 5)
+	if got := dropRatio(t, b, 1000); got != 0 {
+		t.Errorf("K=2: got drop ratio %.3f, want 0", got)
+	}
+	b.MarkFailed()
+	// (11 - 2 * 5) / 12
+	if got, want := dropRatio(t, b, 10000), 1.0/12; math.Abs(got-want) > 0.02 {
+		t.Errorf("K=2: got drop ratio %.3f, want %.3f", got, want)
+	}
+
+	// 非法的窗口与时间片数量被忽略, 不会 panic
+	b = NewSRE(WithWindow(0), WithBucket(-1), WithRequest(1))
+	mark(b, 0, 10)
+	if got := dropRatio(t, b, 1000); got < 0.8 {
+		t.Errorf("default window: got drop ratio %.3f, want about %.3f", got, 10.0/11)
+	}
+}
+
+func TestSRERecover(t *testing.T) {
+	window := 100 * time.Millisecond
+	b := NewSRE(WithWindow(window), WithBucket(5), WithRequest(10))
+	mark(b, 0, 100)
+	if b.Allow(); b.State() != StateOpen {
+		t.Fatalf("got state %d, want open", b.State())
+	}
+	// 失败滑出窗口后恢复
+	time.Sleep(window + window/5)
+	if err := b.Allow(); err != nil {
+		t.Errorf("after window: got error %v", err)
+	}
+	if got := b.State(); got != StateClosed {
+		t.Errorf("after window: got state %d, want closed", got)
+	}
+
+	// 窗口内成功的比例回升后也会恢复
+	mark(b, 0, 100)
+	b.Allow()
+	mark(b, 1000, 0)
+	if got := dropRatio(t, b, 1000); got != 0 {
+		t.Errorf("after success: got drop ratio %.3f, want 0", got)
+	}
+	if got := b.State(); got != StateClosed {
+		t.Errorf("after success: got state %d, want closed", got)
+	}
+}
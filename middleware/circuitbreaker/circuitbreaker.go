@@ -0,0 +1,78 @@
+package circuitbreaker
+
+import (
+	"context"
+
+	"kratos_c/errors"
+	"kratos_c/internal/group"
+	"kratos_c/middleware"
+	"kratos_c/transport"
+)
+
+// ErrNotAllowed is request failed due to circuit breaker triggered.
+var ErrNotAllowed = errors.New(503, "CIRCUITBREAKER", "request failed due to circuit breaker triggered")
+
+// Option is circuit breaker option.
+type Option func(*options)
+
+// WithCircuitBreaker with circuit breaker genFunc.
+// 每个 operation 首次调用时使用 genBreakerFunc 创建独立的熔断器.
+func WithCircuitBreaker(genBreakerFunc func() CircuitBreaker) Option {
+	return func(o *options) {
+		o.group = group.NewGroup(genBreakerFunc)
+	}
+}
+
+type options struct {
+	group *group.Group[CircuitBreaker]
+}
+
+// Client circuitbreaker middleware will return ErrNotAllowed when the circuit
+// breaker is triggered and the request is rejected directly.
+func Client(opts ...Option) middleware.Middleware {
+	opt := &options{
+		group: group.NewGroup(func() CircuitBreaker {
+			return NewSRE()
+		}),
+	}
+	for _, o := range opts {
+		o(opt)
+	}
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			info, ok := transport.FromClientContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+			breaker := opt.group.Get(info.Operation())
+			if err := breaker.Allow(); err != nil {
+				// rejected
+				// NOTE: when client reject requests locally,
+				// continue to add counter let the drop ratio higher.
+				breaker.MarkFailed()
+				return nil, ErrNotAllowed
+			}
+			// allowed
+			reply, err := handler(ctx, req)
+			if isFailure(err) {
+				breaker.MarkFailed()
+			} else {
+				breaker.MarkSuccess()
+			}
+			return reply, err
+		}
+	}
+}
+
+// isFailure 只有 500/503/504 计入失败, 其他错误说明下游能够正常处理请求:
+//   - 业务错误(4xx)是对请求本身的判断, 与下游是否健康无关.
+//   - 429 是下游限流(例如 ratelimit 中间件的 ErrLimitExceed)主动拒绝的请求,
+//     下游已经在自行丢弃超出配额的流量, 再计入熔断会在配额恢复后继续丢弃本可以成功的请求.
+//
+// 非 kratos_c 的错误按 errors.FromError 转换, 普通 error 为 500, gRPC 的
+// Unavailable 与 DeadlineExceeded 分别为 503 与 504.
+func isFailure(err error) bool {
+	return err != nil && (errors.IsInternalServer(err) ||
+		errors.IsServiceUnavailable(err) ||
+		errors.IsGatewayTimeout(err))
+}
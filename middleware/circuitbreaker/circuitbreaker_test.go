@@ -0,0 +1,144 @@
+package circuitbreaker
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"kratos_c/errors"
+	"kratos_c/transport"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type headerCarrier map[string]string
+
+func (h headerCarrier) Get(key string) string { return h[key] }
+func (h headerCarrier) Set(key, value string) { h[key] = value }
+func (h headerCarrier) Add(key, value string) { h[key] = value }
+func (h headerCarrier) Values(key string) []string {
+	if v, ok := h[key]; ok {
+		return []string{v}
+	}
+	return nil
+}
+
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+type testTransport struct {
+	operation string
+}
+
+func (tr *testTransport) Kind() transport.Kind            { return transport.KindGRPC }
+func (tr *testTransport) Endpoint() string                { return "" }
+func (tr *testTransport) Operation() string               { return tr.operation }
+func (tr *testTransport) RequestHeader() transport.Header { return headerCarrier{} }
+func (tr *testTransport) ReplyHeader() transport.Header   { return headerCarrier{} }
+
+// testBreaker 记录调用结果, allow 不为 nil 时拒绝请求
+type testBreaker struct {
+	allow   error
+	success int
+	failed  int
+}
+
+func (b *testBreaker) Allow() error { return b.allow }
+func (b *testBreaker) MarkSuccess() { b.success++ }
+func (b *testBreaker) MarkFailed()  { b.failed++ }
+
+func TestIsFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"internal server", errors.InternalServer("INTERNAL", ""), true},
+		{"service unavailable", errors.ServiceUnavailable("UNAVAILABLE", ""), true},
+		{"gateway timeout", errors.GatewayTimeout("TIMEOUT", ""), true},
+		{"wrapped", fmt.Errorf("call: %w", errors.ServiceUnavailable("UNAVAILABLE", "")), true},
+		{"plain error", stderrors.New("connection reset"), true},
+		{"grpc unavailable", status.Error(codes.Unavailable, ""), true},
+		{"grpc deadline exceeded", status.Error(codes.DeadlineExceeded, ""), true},
+		// 下游限流不计入失败
+		{"too many requests", errors.New(429, "RATELIMIT", ""), false},
+		{"grpc resource exhausted", status.Error(codes.ResourceExhausted, ""), false},
+		{"bad request", errors.BadRequest("BAD_REQUEST", ""), false},
+		{"not found", errors.NotFound("NOT_FOUND", ""), false},
+		{"unauthorized", errors.Unauthorized("UNAUTHORIZED", ""), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isFailure(tt.err); got != tt.want {
+				t.Errorf("isFailure(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient(t *testing.T) {
+	breakers := map[string]*testBreaker{}
+	m := Client(WithCircuitBreaker(func() CircuitBreaker {
+		b := &testBreaker{}
+		breakers[fmt.Sprint(len(breakers))] = b
+		return b
+	}))
+	call := func(operation string, err error) (called bool, got error) {
+		ctx := transport.NewClientContext(context.Background(), &testTransport{operation: operation})
+		_, got = m(func(context.Context, any) (any, error) {
+			called = true
+			return nil, err
+		})(ctx, nil)
+		return
+	}
+
+	if _, err := call("/a", nil); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = call("/a", errors.ServiceUnavailable("UNAVAILABLE", ""))
+	_, _ = call("/a", errors.New(429, "RATELIMIT", ""))
+	_, _ = call("/a", errors.BadRequest("BAD_REQUEST", ""))
+	a := breakers["0"]
+	if a.success != 3 || a.failed != 1 {
+		t.Errorf("got %d success and %d failed, want 3 and 1", a.success, a.failed)
+	}
+
+	// 每个 operation 使用独立的熔断器
+	_, _ = call("/b", nil)
+	if len(breakers) != 2 || breakers["1"].success != 1 || a.success != 3 {
+		t.Errorf("got %d breakers, want one per operation", len(breakers))
+	}
+
+	// 熔断时不调用下游, 本地拒绝也计入失败
+	a.allow = ErrBreakerOpen
+	called, err := call("/a", nil)
+	if called {
+		t.Error("got the handler called while the breaker is open")
+	}
+	if !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("got error %v, want %v", err, ErrNotAllowed)
+	}
+	if a.failed != 2 {
+		t.Errorf("got %d failed, want 2", a.failed)
+	}
+}
+
+func TestClientWithoutTransport(t *testing.T) {
+	b := &testBreaker{allow: ErrBreakerOpen}
+	m := Client(WithCircuitBreaker(func() CircuitBreaker { return b }))
+	reply, err := m(func(context.Context, any) (any, error) { return "reply", nil })(context.Background(), nil)
+	if err != nil || reply != "reply" {
+		t.Errorf("got reply %v, error %v", reply, err)
+	}
+	if b.success != 0 || b.failed != 0 {
+		t.Errorf("got %d success and %d failed, want the breaker untouched", b.success, b.failed)
+	}
+}
@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"kratos_c/errors"
+	"kratos_c/log"
+	"kratos_c/middleware"
+	"kratos_c/transport"
+)
+
+// Redacter defines how to log an object
+type Redacter interface {
+	Redact() string
+}
+
+// RedactFunc 将请求参数转换为可以写入日志的字符串, 用于脱敏
+type RedactFunc func(req any) string
+
+// Option is logging option.
+type Option func(*options)
+
+type options struct {
+	redact RedactFunc
+}
+
+// WithRedactFunc with request args redact func.
+// 优先级高于请求自身实现的 Redacter.
+func WithRedactFunc(f RedactFunc) Option {
+	return func(o *options) {
+		o.redact = f
+	}
+}
+
+// Server is an server logging middleware.
+func Server(logger log.Logger, opts ...Option) middleware.Middleware {
+	return logging("server", transport.FromServerContext, logger, opts)
+}
+
+// Client is a client logging middleware.
+func Client(logger log.Logger, opts ...Option) middleware.Middleware {
+	return logging("client", transport.FromClientContext, logger, opts)
+}
+
+func logging(side string, from func(context.Context) (transport.Transporter, bool), logger log.Logger, opts []Option) middleware.Middleware {
+	o := options{redact: extractArgs}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (reply any, err error) {
+			var (
+				code      int32
+				reason    string
+				kind      string
+				operation string
+			)
+			startTime := time.Now()
+			if info, ok := from(ctx); ok {
+				kind = info.Kind().String()
+				operation = info.Operation()
+			}
+			reply, err = handler(ctx, req)
+			if se := errors.FromError(err); se != nil {
+				code = se.Code
+				reason = se.Reason
+			}
+			level, stack := extractError(err)
+			_ = log.WithContext(ctx, logger).Log(level,
+				"kind", side,
+				"component", kind,
+				"operation", operation,
+				"args", o.redact(req),
+				"code", code,
+				"reason", reason,
+				"stack", stack,
+				"latency", time.Since(startTime).Seconds(),
+			)
+			return
+		}
+	}
+}
+
+// extractArgs returns the string of the req
+func extractArgs(req any) string {
+	if redacter, ok := req.(Redacter); ok {
+		return redacter.Redact()
+	}
+	if stringer, ok := req.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprintf("%+v", req)
+}
+
+// extractError returns the string of the error
+func extractError(err error) (log.Level, string) {
+	if err != nil {
+		return log.LevelError, fmt.Sprintf("%+v", err)
+	}
+	return log.LevelInfo, ""
+}
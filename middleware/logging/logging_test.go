@@ -0,0 +1,197 @@
+package logging
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"testing"
+	"time"
+
+	"kratos_c/errors"
+	"kratos_c/log"
+	"kratos_c/transport"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type headerCarrier map[string]string
+
+func (h headerCarrier) Get(key string) string { return h[key] }
+func (h headerCarrier) Set(key, value string) { h[key] = value }
+func (h headerCarrier) Add(key, value string) { h[key] = value }
+func (h headerCarrier) Values(key string) []string {
+	if v, ok := h[key]; ok {
+		return []string{v}
+	}
+	return nil
+}
+
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+type testTransport struct {
+	kind      transport.Kind
+	operation string
+}
+
+func (tr *testTransport) Kind() transport.Kind            { return tr.kind }
+func (tr *testTransport) Endpoint() string                { return "" }
+func (tr *testTransport) Operation() string               { return tr.operation }
+func (tr *testTransport) RequestHeader() transport.Header { return headerCarrier{} }
+func (tr *testTransport) ReplyHeader() transport.Header   { return headerCarrier{} }
+
+// testLogger 记录最后一条日志
+type testLogger struct {
+	level  log.Level
+	fields map[string]any
+}
+
+func (l *testLogger) Log(level log.Level, keyvals ...any) error {
+	l.level = level
+	l.fields = make(map[string]any, len(keyvals)/2)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		l.fields[keyvals[i].(string)] = keyvals[i+1]
+	}
+	return nil
+}
+
+type request struct {
+	Name     string
+	Password string
+}
+
+// redactedRequest 实现了 Redacter, 日志中不输出密码
+type redactedRequest request
+
+func (r redactedRequest) Redact() string {
+	return "name:" + r.Name
+}
+
+func TestServer(t *testing.T) {
+	logger := &testLogger{}
+	ctx := transport.NewServerContext(context.Background(), &testTransport{kind: transport.KindGRPC, operation: "/helloworld.Greeter/SayHello"})
+	reply, err := Server(logger)(func(context.Context, any) (any, error) {
+		return "reply", nil
+	})(ctx, request{Name: "kratos"})
+	if err != nil || reply != "reply" {
+		t.Fatalf("got reply %v, error %v", reply, err)
+	}
+	if logger.level != log.LevelInfo {
+		t.Errorf("got level %s, want %s", logger.level, log.LevelInfo)
+	}
+	want := map[string]any{
+		"kind":      "server",
+		"component": "grpc",
+		"operation": "/helloworld.Greeter/SayHello",
+		"args":      "{Name:kratos Password:}",
+		"code":      int32(0),
+		"reason":    "",
+		"stack":     "",
+	}
+	for k, v := range want {
+		if got := logger.fields[k]; got != v {
+			t.Errorf("field %s: got %#v, want %#v", k, got, v)
+		}
+	}
+	if _, ok := logger.fields["latency"].(float64); !ok {
+		t.Errorf("field latency: got %#v, want float64 seconds", logger.fields["latency"])
+	}
+}
+
+func TestClient(t *testing.T) {
+	logger := &testLogger{}
+	handler := func(context.Context, any) (any, error) { return nil, nil }
+	ctx := transport.NewClientContext(context.Background(), &testTransport{kind: transport.KindHTTP, operation: "/api/users"})
+	if _, err := Client(logger)(handler)(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if logger.fields["kind"] != "client" || logger.fields["component"] != "http" || logger.fields["operation"] != "/api/users" {
+		t.Errorf("got fields %v", logger.fields)
+	}
+
+	// client 中间件不读取 server transport
+	ctx = transport.NewServerContext(context.Background(), &testTransport{kind: transport.KindGRPC, operation: "/server"})
+	if _, err := Client(logger)(handler)(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if logger.fields["component"] != "" || logger.fields["operation"] != "" {
+		t.Errorf("got fields %v, want no transport info", logger.fields)
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   int32
+		reason string
+	}{
+		{"kratos error", errors.NotFound("USER_NOT_FOUND", "user not found"), 404, "USER_NOT_FOUND"},
+		{"wrapped kratos error", errors.Unauthorized("UNAUTHORIZED", "no token").WithCause(stderrors.New("cause")), 401, "UNAUTHORIZED"},
+		{"grpc status", status.Error(codes.InvalidArgument, "bad"), 400, errors.UnknownReason},
+		{"plain error", stderrors.New("plain"), errors.UnknownCode, errors.UnknownReason},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &testLogger{}
+			_, err := Server(logger)(func(context.Context, any) (any, error) {
+				return nil, tt.err
+			})(context.Background(), nil)
+			if err != tt.err {
+				t.Errorf("got error %v, want the handler error", err)
+			}
+			if logger.level != log.LevelError {
+				t.Errorf("got level %s, want %s", logger.level, log.LevelError)
+			}
+			if got := logger.fields["code"]; got != tt.code {
+				t.Errorf("got code %#v, want %d", got, tt.code)
+			}
+			if got := logger.fields["reason"]; got != tt.reason {
+				t.Errorf("got reason %#v, want %q", got, tt.reason)
+			}
+			if stack, _ := logger.fields["stack"].(string); stack == "" {
+				t.Error("got empty stack, want the error")
+			}
+		})
+	}
+}
+
+func TestLatency(t *testing.T) {
+	logger := &testLogger{}
+	_, _ = Server(logger)(func(context.Context, any) (any, error) {
+		time.Sleep(20 * time.Millisecond)
+		return nil, nil
+	})(context.Background(), nil)
+	latency, _ := logger.fields["latency"].(float64)
+	if latency < 0.02 || latency > 1 {
+		t.Errorf("got latency %v, want about 0.02 seconds", latency)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	handler := func(context.Context, any) (any, error) { return nil, nil }
+	req := request{Name: "kratos", Password: "secret"}
+
+	logger := &testLogger{}
+	_, _ = Server(logger)(handler)(context.Background(), redactedRequest(req))
+	if got, _ := logger.fields["args"].(string); got != "name:kratos" || strings.Contains(got, "secret") {
+		t.Errorf("Redacter: got args %#v", got)
+	}
+
+	// WithRedactFunc 优先于 Redacter
+	_, _ = Server(logger, WithRedactFunc(func(any) string { return "***" }))(handler)(context.Background(), redactedRequest(req))
+	if got := logger.fields["args"]; got != "***" {
+		t.Errorf("WithRedactFunc: got args %#v", got)
+	}
+
+	_, _ = Server(logger)(handler)(context.Background(), time.Second)
+	if got := logger.fields["args"]; got != "1s" {
+		t.Errorf("Stringer: got args %#v", got)
+	}
+}
@@ -0,0 +1,121 @@
+package metadata
+
+import (
+	"context"
+	"strings"
+
+	"kratos_c/metadata"
+	"kratos_c/middleware"
+	"kratos_c/transport"
+)
+
+// Option is metadata option.
+type Option func(*options)
+
+type options struct {
+	prefix []string
+	md     metadata.Metadata
+}
+
+func (o *options) hasPrefix(key string) bool {
+	k := strings.ToLower(key)
+	for _, prefix := range o.prefix {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithConstants with constant metadata key value, key 会转换为小写.
+func WithConstants(md metadata.Metadata) Option {
+	return func(o *options) {
+		// New 通过 Add 复制并把 key 转换为小写, 与请求头中的 key 保持一致
+		o.md = metadata.New(md)
+	}
+}
+
+// WithPropagatedPrefix with propagated key prefix.
+func WithPropagatedPrefix(prefix ...string) Option {
+	return func(o *options) {
+		// 复制一份, 不修改调用方的切片
+		o.prefix = make([]string, 0, len(prefix))
+		for _, p := range prefix {
+			o.prefix = append(o.prefix, strings.ToLower(p))
+		}
+	}
+}
+
+// Server is middleware server-side metadata.
+// 默认接收 x-md- 开头的头部: x-md-global- 会继续向下游传递, x-md-local- 只在当前服务可见.
+func Server(opts ...Option) middleware.Middleware {
+	options := &options{
+		prefix: []string{"x-md-"}, // x-md-global-, x-md-local-
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+			md := options.md.Clone()
+			header := tr.RequestHeader()
+			for _, k := range header.Keys() {
+				if options.hasPrefix(k) {
+					for _, v := range header.Values(k) {
+						md.Add(k, v)
+					}
+				}
+			}
+			ctx = metadata.NewServerContext(ctx, md)
+			return handler(ctx, req)
+		}
+	}
+}
+
+// Client is middleware client-side metadata.
+// 常量与客户端 metadata 总是写入请求头部, 服务端 metadata 中只有匹配前缀(默认 x-md-global-)的才会继续传递.
+func Client(opts ...Option) middleware.Middleware {
+	options := &options{
+		prefix: []string{"x-md-global-"},
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			tr, ok := transport.FromClientContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+			header := tr.RequestHeader()
+			// x-md-local-
+			for k, vList := range options.md {
+				for _, v := range vList {
+					header.Add(k, v)
+				}
+			}
+			if md, ok := metadata.FromClientContext(ctx); ok {
+				for k, vList := range md {
+					for _, v := range vList {
+						header.Add(k, v)
+					}
+				}
+			}
+			// x-md-global-
+			if md, ok := metadata.FromServerContext(ctx); ok {
+				for k, vList := range md {
+					if options.hasPrefix(k) {
+						for _, v := range vList {
+							header.Add(k, v)
+						}
+					}
+				}
+			}
+			return handler(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package metadata
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"kratos_c/metadata"
+	"kratos_c/transport"
+)
+
+type headerCarrier map[string][]string
+
+func (h headerCarrier) Get(key string) string {
+	if v := h[key]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+func (h headerCarrier) Set(key, value string)      { h[key] = []string{value} }
+func (h headerCarrier) Add(key, value string)      { h[key] = append(h[key], value) }
+func (h headerCarrier) Values(key string) []string { return h[key] }
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+type testTransport struct {
+	header headerCarrier
+}
+
+func (tr *testTransport) Kind() transport.Kind            { return transport.KindGRPC }
+func (tr *testTransport) Endpoint() string                { return "" }
+func (tr *testTransport) Operation() string               { return "" }
+func (tr *testTransport) RequestHeader() transport.Header { return tr.header }
+func (tr *testTransport) ReplyHeader() transport.Header   { return headerCarrier{} }
+
+func TestWithConstants(t *testing.T) {
+	constants := metadata.Metadata{"X-MD-Global-Region": {"eu"}, "x-md-global-region": {"us"}}
+	var got metadata.Metadata
+	_, err := Server(WithConstants(constants))(func(ctx context.Context, _ any) (any, error) {
+		got, _ = metadata.FromServerContext(ctx)
+		return nil, nil
+	})(transport.NewServerContext(context.Background(), &testTransport{header: headerCarrier{}}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values := got.Values("x-md-global-region"); len(values) != 2 {
+		t.Errorf("got values %q, want both constants under the lower case key", values)
+	}
+	if _, ok := got["X-MD-Global-Region"]; ok {
+		t.Error("constant key is not normalized to lower case")
+	}
+	// 不修改调用方的 map
+	if len(constants["X-MD-Global-Region"]) != 1 {
+		t.Errorf("caller's metadata is modified: %v", constants)
+	}
+}
+
+func TestClient(t *testing.T) {
+	header := headerCarrier{}
+	ctx := transport.NewClientContext(context.Background(), &testTransport{header: header})
+	ctx = metadata.NewServerContext(ctx, metadata.New(map[string][]string{
+		"x-md-global-trace": {"1"},
+		"x-md-local-user":   {"2"},
+	}))
+	ctx = metadata.AppendToClientContext(ctx, "x-md-client", "3")
+	m := Client(WithConstants(metadata.Metadata{"X-MD-Constant": {"4"}}))
+	if _, err := m(func(context.Context, any) (any, error) { return nil, nil })(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := headerCarrier{
+		"x-md-constant":     {"4"},
+		"x-md-client":       {"3"},
+		"x-md-global-trace": {"1"},
+	}
+	if !reflect.DeepEqual(header, want) {
+		t.Errorf("got header %v, want %v", header, want)
+	}
+}
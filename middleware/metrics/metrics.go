@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"kratos_c/errors"
+	"kratos_c/metrics"
+	"kratos_c/middleware"
+	"kratos_c/transport"
+)
+
+// Labels 是 requests 与 seconds 的标签名, 注册指标时按这个顺序声明.
+var Labels = []string{"kind", "operation", "code", "reason"}
+
+// Option is metrics option.
+type Option func(*options)
+
+// WithRequests with requests counter.
+func WithRequests(c metrics.Counter) Option {
+	return func(o *options) {
+		o.requests = c
+	}
+}
+
+// WithSeconds with seconds histogram.
+func WithSeconds(c metrics.Observer) Option {
+	return func(o *options) {
+		o.seconds = c
+	}
+}
+
+type options struct {
+	// counter: <client/server>_requests_code_total{kind, operation, code, reason}
+	requests metrics.Counter
+	// histogram: <client/server>_requests_seconds_bucket{kind, operation, code, reason}
+	seconds metrics.Observer
+}
+
+// Server is middleware server-side metrics.
+func Server(opts ...Option) middleware.Middleware {
+	return record(transport.FromServerContext, opts)
+}
+
+// Client is middleware client-side metrics.
+func Client(opts ...Option) middleware.Middleware {
+	return record(transport.FromClientContext, opts)
+}
+
+func record(from func(context.Context) (transport.Transporter, bool), opts []Option) middleware.Middleware {
+	op := options{}
+	for _, o := range opts {
+		o(&op)
+	}
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			var (
+				kind      string
+				operation string
+			)
+			startTime := time.Now()
+			if info, ok := from(ctx); ok {
+				kind = info.Kind().String()
+				operation = info.Operation()
+			}
+			reply, err := handler(ctx, req)
+			// 成功时 code 为 200
+			code := strconv.Itoa(errors.Code(err))
+			reason := errors.Reason(err)
+			if op.requests != nil {
+				op.requests.With(kind, operation, code, reason).Inc()
+			}
+			if op.seconds != nil {
+				op.seconds.With(kind, operation, code, reason).Observe(time.Since(startTime).Seconds())
+			}
+			return reply, err
+		}
+	}
+}
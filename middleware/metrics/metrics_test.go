@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"kratos_c/errors"
+	"kratos_c/metrics/memory"
+	"kratos_c/transport"
+)
+
+type headerCarrier map[string]string
+
+func (h headerCarrier) Get(key string) string { return h[key] }
+func (h headerCarrier) Set(key, value string) { h[key] = value }
+func (h headerCarrier) Add(key, value string) { h[key] = value }
+func (h headerCarrier) Values(key string) []string {
+	if v, ok := h[key]; ok {
+		return []string{v}
+	}
+	return nil
+}
+
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+type testTransport struct {
+	kind      transport.Kind
+	operation string
+}
+
+func (tr *testTransport) Kind() transport.Kind            { return tr.kind }
+func (tr *testTransport) Endpoint() string                { return "" }
+func (tr *testTransport) Operation() string               { return tr.operation }
+func (tr *testTransport) RequestHeader() transport.Header { return headerCarrier{} }
+func (tr *testTransport) ReplyHeader() transport.Header   { return headerCarrier{} }
+
+func output(t *testing.T, r *memory.Registry) string {
+	t.Helper()
+	var b strings.Builder
+	if _, err := r.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestServer(t *testing.T) {
+	r := memory.New()
+	m := Server(
+		WithRequests(r.NewCounter("server_requests_code_total", "", Labels...)),
+		WithSeconds(r.NewHistogram("server_requests_seconds", "", []float64{60}, Labels...)),
+	)
+	ctx := transport.NewServerContext(context.Background(), &testTransport{kind: transport.KindGRPC, operation: "/helloworld.Greeter/SayHello"})
+	ok := func(context.Context, any) (any, error) { return "reply", nil }
+	fail := func(context.Context, any) (any, error) { return nil, errors.NotFound("USER_NOT_FOUND", "user not found") }
+
+	for range 2 {
+		if reply, err := m(ok)(ctx, nil); err != nil || reply != "reply" {
+			t.Fatalf("got reply %v, error %v", reply, err)
+		}
+	}
+	if _, err := m(fail)(ctx, nil); errors.Reason(err) != "USER_NOT_FOUND" {
+		t.Fatalf("got error %v, want the handler error", err)
+	}
+
+	got := output(t, r)
+	for _, want := range []string{
+		`server_requests_code_total{kind="grpc",operation="/helloworld.Greeter/SayHello",code="200",reason=""} 2`,
+		`server_requests_code_total{kind="grpc",operation="/helloworld.Greeter/SayHello",code="404",reason="USER_NOT_FOUND"} 1`,
+		`server_requests_seconds_count{kind="grpc",operation="/helloworld.Greeter/SayHello",code="200",reason=""} 2`,
+		`server_requests_seconds_bucket{kind="grpc",operation="/helloworld.Greeter/SayHello",code="404",reason="USER_NOT_FOUND",le="60"} 1`,
+	} {
+		if !strings.Contains(got, want+"\n") {
+			t.Errorf("missing %s in\n%s", want, got)
+		}
+	}
+}
+
+func TestClient(t *testing.T) {
+	r := memory.New()
+	m := Client(WithRequests(r.NewCounter("client_requests_code_total", "", Labels...)))
+	handler := func(context.Context, any) (any, error) { return nil, nil }
+
+	clientCtx := transport.NewClientContext(context.Background(), &testTransport{kind: transport.KindHTTP, operation: "/api/users"})
+	if _, err := m(handler)(clientCtx, nil); err != nil {
+		t.Fatal(err)
+	}
+	// client 中间件只读取 client transport
+	serverCtx := transport.NewServerContext(context.Background(), &testTransport{kind: transport.KindGRPC, operation: "/server"})
+	if _, err := m(handler)(serverCtx, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	got := output(t, r)
+	for _, want := range []string{
+		`client_requests_code_total{kind="http",operation="/api/users",code="200",reason=""} 1`,
+		`client_requests_code_total{kind="",operation="",code="200",reason=""} 1`,
+	} {
+		if !strings.Contains(got, want+"\n") {
+			t.Errorf("missing %s in\n%s", want, got)
+		}
+	}
+}
+
+func TestWithoutMetrics(t *testing.T) {
+	// 没有配置指标时只透传请求
+	reply, err := Server()(func(context.Context, any) (any, error) { return "reply", nil })(context.Background(), nil)
+	if err != nil || reply != "reply" {
+		t.Errorf("got reply %v, error %v", reply, err)
+	}
+}
@@ -0,0 +1,165 @@
+package ratelimit
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+
+	"kratos_c/internal/cpu"
+	"kratos_c/internal/window"
+)
+
+var _ Limiter = (*BBR)(nil)
+
+// BBROption is bbr limiter option.
+type BBROption func(*bbrOptions)
+
+type bbrOptions struct {
+	// window 统计窗口的长度
+	window time.Duration
+	// bucket 统计窗口的时间片数量
+	bucket int
+	// cpuThreshold CPU 使用率阈值, 千分比
+	cpuThreshold uint64
+	// cpu 返回当前 CPU 使用率, 千分比
+	cpu func() uint64
+}
+
+// WithWindow with window size, 小于等于 0 时忽略.
+func WithWindow(d time.Duration) BBROption {
+	return func(o *bbrOptions) {
+		if d > 0 {
+			o.window = d
+		}
+	}
+}
+
+// WithBucket with bucket size, 小于等于 0 时忽略.
+func WithBucket(b int) BBROption {
+	return func(o *bbrOptions) {
+		if b > 0 {
+			o.bucket = b
+		}
+	}
+}
+
+// WithCPUThreshold with cpu threshold, 800 表示 80%.
+func WithCPUThreshold(threshold uint64) BBROption {
+	return func(o *bbrOptions) {
+		o.cpuThreshold = threshold
+	}
+}
+
+// WithCPUFunc with the func returns current cpu usage, 默认读取系统的 CPU 使用率.
+func WithCPUFunc(f func() uint64) BBROption {
+	return func(o *bbrOptions) {
+		o.cpu = f
+	}
+}
+
+// BBR 是参考 TCP BBR 的自适应限流器:
+// CPU 超过阈值(或刚刚发生过丢弃)时, 若在途请求数超过 最大通过数 * 最小 RT 估算出的系统容量, 则拒绝请求.
+type BBR struct {
+	opts            bbrOptions
+	passStat        *window.Rolling
+	rtStat          *window.Rolling
+	inFlight        atomic.Int64
+	bucketPerSecond float64
+	prevDropTime    atomic.Int64
+}
+
+// NewBBR returns a bbr limiter
+func NewBBR(opts ...BBROption) *BBR {
+	o := bbrOptions{
+		window:       10 * time.Second,
+		bucket:       100,
+		cpuThreshold: 800,
+		cpu:          cpu.Usage,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	bucketDuration := o.window / time.Duration(o.bucket)
+	if bucketDuration <= 0 {
+		// window 比 bucket 数量(纳秒)还短
+		bucketDuration = 1
+	}
+	return &BBR{
+		opts:            o,
+		passStat:        window.NewRolling(o.bucket, bucketDuration),
+		rtStat:          window.NewRolling(o.bucket, bucketDuration),
+		bucketPerSecond: float64(time.Second) / float64(bucketDuration),
+	}
+}
+
+// maxPASS 返回已完成时间片中单个时间片的最大通过数
+func (l *BBR) maxPASS() float64 {
+	maxPass := 1.0
+	l.passStat.Range(true, func(b window.Bucket) {
+		if b.Sum > maxPass {
+			maxPass = b.Sum
+		}
+	})
+	return maxPass
+}
+
+// minRT 返回已完成时间片中最小的平均 RT, 单位毫秒
+func (l *BBR) minRT() float64 {
+	minRT := math.MaxFloat64
+	l.rtStat.Range(true, func(b window.Bucket) {
+		if b.Count == 0 {
+			return
+		}
+		if avg := math.Ceil(b.Sum / float64(b.Count)); avg < minRT {
+			minRT = avg
+		}
+	})
+	if minRT == math.MaxFloat64 {
+		return 1
+	}
+	return minRT
+}
+
+// maxInFlight 根据 Little's law 估算的最大在途请求数
+func (l *BBR) maxInFlight() int64 {
+	return int64(math.Floor(l.maxPASS()*l.minRT()*l.bucketPerSecond/1000.0 + 0.5))
+}
+
+func (l *BBR) shouldDrop() bool {
+	now := time.Now().UnixNano()
+	inFlight := l.inFlight.Load()
+	if l.opts.cpu() < l.opts.cpuThreshold {
+		prevDropTime := l.prevDropTime.Load()
+		if prevDropTime == 0 {
+			return false
+		}
+		// 丢弃之后 1s 的冷却期内仍按容量判断, 避免 CPU 刚降下来又被打满
+		if time.Duration(now-prevDropTime) <= time.Second {
+			return inFlight > 1 && inFlight > l.maxInFlight()
+		}
+		l.prevDropTime.Store(0)
+		return false
+	}
+	drop := inFlight > 1 && inFlight > l.maxInFlight()
+	if drop {
+		l.prevDropTime.CompareAndSwap(0, now)
+	}
+	return drop
+}
+
+// Allow checks all inbound traffic.
+// Once overload is detected, it returns ErrLimitExceed.
+func (l *BBR) Allow() (DoneFunc, error) {
+	if l.shouldDrop() {
+		return nil, ErrLimitExceed
+	}
+	l.inFlight.Add(1)
+	start := time.Now()
+	return func(DoneInfo) {
+		if rt := math.Ceil(float64(time.Since(start)) / float64(time.Millisecond)); rt > 0 {
+			l.rtStat.Add(rt)
+		}
+		l.inFlight.Add(-1)
+		l.passStat.Add(1)
+	}, nil
+}
@@ -0,0 +1,127 @@
+package ratelimit
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"kratos_c/errors"
+)
+
+func newTestBBR(cpu *atomic.Uint64) *BBR {
+	return NewBBR(
+		WithWindow(time.Second),
+		WithBucket(10),
+		WithCPUThreshold(800),
+		WithCPUFunc(cpu.Load),
+	)
+}
+
+func allow(t *testing.T, l *BBR, n int) []DoneFunc {
+	t.Helper()
+	dones := make([]DoneFunc, 0, n)
+	for i := range n {
+		done, err := l.Allow()
+		if err != nil {
+			t.Fatalf("request %d: got error %v", i, err)
+		}
+		dones = append(dones, done)
+	}
+	return dones
+}
+
+func TestBBRLowCPU(t *testing.T) {
+	var cpu atomic.Uint64
+	cpu.Store(100)
+	l := newTestBBR(&cpu)
+	// CPU 没有超过阈值时不限流
+	allow(t, l, 100)
+	if got := l.inFlight.Load(); got != 100 {
+		t.Errorf("got in flight %d, want 100", got)
+	}
+}
+
+func TestBBRDrop(t *testing.T) {
+	var cpu atomic.Uint64
+	cpu.Store(900)
+	l := newTestBBR(&cpu)
+	// 没有历史统计时 maxPASS 和 minRT 都是 1, 容量为 0, 最多允许 1 个在途请求
+	if got := l.maxInFlight(); got != 0 {
+		t.Fatalf("got max in flight %d, want 0", got)
+	}
+	dones := allow(t, l, 2)
+	if _, err := l.Allow(); !errors.Is(err, ErrLimitExceed) {
+		t.Fatalf("got error %v, want %v", err, ErrLimitExceed)
+	}
+	if l.prevDropTime.Load() == 0 {
+		t.Error("prevDropTime is not recorded after a drop")
+	}
+
+	// CPU 降下来后的冷却期内仍然按容量判断
+	cpu.Store(100)
+	if _, err := l.Allow(); !errors.Is(err, ErrLimitExceed) {
+		t.Errorf("cooling down: got error %v, want %v", err, ErrLimitExceed)
+	}
+	for _, done := range dones {
+		done(DoneInfo{})
+	}
+	if got := l.inFlight.Load(); got != 0 {
+		t.Fatalf("got in flight %d after done, want 0", got)
+	}
+	dones = allow(t, l, 2)
+
+	// 冷却期结束后不再限流
+	l.prevDropTime.Store(time.Now().Add(-2 * time.Second).UnixNano())
+	allow(t, l, 10)
+	if l.prevDropTime.Load() != 0 {
+		t.Error("prevDropTime is not reset after the cool down")
+	}
+	for _, done := range dones {
+		done(DoneInfo{})
+	}
+}
+
+func TestBBRMaxInFlight(t *testing.T) {
+	var cpu atomic.Uint64
+	l := newTestBBR(&cpu)
+	for range 50 {
+		l.passStat.Add(1)
+	}
+	l.rtStat.Add(10)
+	l.rtStat.Add(30)
+	// 只统计已经结束的时间片
+	if got := l.maxPASS(); got != 1 {
+		t.Errorf("current bucket: got max pass %v, want 1", got)
+	}
+	time.Sleep(l.passStat.BucketDuration() * 3 / 2)
+	if got := l.maxPASS(); got != 50 {
+		t.Errorf("got max pass %v, want 50", got)
+	}
+	if got := l.minRT(); got != 20 {
+		t.Errorf("got min rt %v, want 20", got)
+	}
+	// 50 * 20ms * 10 个时间片每秒 / 1000
+	if got := l.maxInFlight(); got != 10 {
+		t.Errorf("got max in flight %d, want 10", got)
+	}
+}
+
+func TestBBRDone(t *testing.T) {
+	var cpu atomic.Uint64
+	l := newTestBBR(&cpu)
+	done, err := l.Allow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	done(DoneInfo{})
+	if got := l.inFlight.Load(); got != 0 {
+		t.Errorf("got in flight %d, want 0", got)
+	}
+	if sum, count := l.passStat.Sum(); sum != 1 || count != 1 {
+		t.Errorf("got pass sum %v count %d, want 1", sum, count)
+	}
+	if sum, count := l.rtStat.Sum(); sum < 2 || count != 1 {
+		t.Errorf("got rt sum %v count %d, want at least 2ms", sum, count)
+	}
+}
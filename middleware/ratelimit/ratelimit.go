@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+
+	"kratos_c/errors"
+	"kratos_c/middleware"
+)
+
+// ErrLimitExceed is service unavailable due to rate limit exceeded.
+var ErrLimitExceed = errors.New(429, "RATELIMIT", "service unavailable due to rate limit exceeded")
+
+// DoneInfo 是请求结束时的信息
+type DoneInfo struct {
+	Err error
+}
+
+// DoneFunc 在请求结束时调用, 用于统计 RT 与在途请求数
+type DoneFunc func(DoneInfo)
+
+// Limiter is a rate limiter.
+type Limiter interface {
+	// Allow 判断请求是否可以通过, 通过时返回的 DoneFunc 必须在请求结束时调用
+	Allow() (DoneFunc, error)
+}
+
+// Option is ratelimit option.
+type Option func(*options)
+
+// WithLimiter set Limiter implementation,
+// default is bbr limiter
+func WithLimiter(limiter Limiter) Option {
+	return func(o *options) {
+		o.limiter = limiter
+	}
+}
+
+type options struct {
+	limiter Limiter
+}
+
+// Server ratelimiter middleware
+func Server(opts ...Option) middleware.Middleware {
+	options := &options{}
+	for _, o := range opts {
+		o(options)
+	}
+	if options.limiter == nil {
+		options.limiter = NewBBR()
+	}
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (reply any, err error) {
+			done, e := options.limiter.Allow()
+			if e != nil {
+				// rejected
+				return nil, ErrLimitExceed.WithCause(e)
+			}
+			// allowed, handler panic 时也要释放在途请求
+			defer func() {
+				done(DoneInfo{Err: err})
+			}()
+			return handler(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"kratos_c/errors"
+)
+
+type testLimiter struct {
+	err  error
+	done []DoneInfo
+}
+
+func (l *testLimiter) Allow() (DoneFunc, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	return func(info DoneInfo) {
+		l.done = append(l.done, info)
+	}, nil
+}
+
+func TestServerReject(t *testing.T) {
+	cause := stderrors.New("overloaded")
+	m := Server(WithLimiter(&testLimiter{err: cause}))
+	_, err := m(func(context.Context, any) (any, error) {
+		t.Fatal("handler called for a rejected request")
+		return nil, nil
+	})(context.Background(), nil)
+	if !errors.Is(err, ErrLimitExceed) {
+		t.Errorf("got error %v, want %v", err, ErrLimitExceed)
+	}
+	if !stderrors.Is(err, cause) {
+		t.Errorf("got error %v, want the limiter error as cause", err)
+	}
+	if code := errors.Code(err); code != 429 {
+		t.Errorf("got code %d, want 429", code)
+	}
+}
+
+func TestServerDone(t *testing.T) {
+	limiter := &testLimiter{}
+	m := Server(WithLimiter(limiter))
+	handlerErr := errors.InternalServer("INTERNAL", "internal")
+	if _, err := m(func(context.Context, any) (any, error) {
+		return nil, handlerErr
+	})(context.Background(), nil); err != handlerErr {
+		t.Errorf("got error %v, want %v", err, handlerErr)
+	}
+
+	// handler panic 时也要调用 done
+	func() {
+		defer func() { _ = recover() }()
+		_, _ = m(func(context.Context, any) (any, error) {
+			panic("boom")
+		})(context.Background(), nil)
+	}()
+	if len(limiter.done) != 2 {
+		t.Fatalf("got %d done calls, want 2", len(limiter.done))
+	}
+	if limiter.done[0].Err != handlerErr {
+		t.Errorf("got done error %v, want %v", limiter.done[0].Err, handlerErr)
+	}
+}
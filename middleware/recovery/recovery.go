@@ -0,0 +1,83 @@
+package recovery
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"kratos_c/errors"
+	"kratos_c/log"
+	"kratos_c/middleware"
+	"kratos_c/transport"
+)
+
+// Latency is recovery latency context key
+type Latency struct{}
+
+// ErrUnknownRequest is unknown request error.
+var ErrUnknownRequest = errors.InternalServer("UNKNOWN", "unknown request error")
+
+// HandlerFunc is recovery handler func.
+type HandlerFunc func(ctx context.Context, req, err any) error
+
+// Option is recovery option.
+type Option func(*options)
+
+type options struct {
+	handler HandlerFunc
+}
+
+// WithHandler with recovery handler.
+func WithHandler(h HandlerFunc) Option {
+	return func(o *options) {
+		o.handler = h
+	}
+}
+
+// Recovery is a server middleware that recovers from any panics.
+// panic 的堆栈会连同 operation 一起记录到日志, 返回给调用方的是 handler 映射出的错误.
+// 请求内容可能包含凭证和个人信息, 不会写入日志.
+func Recovery(opts ...Option) middleware.Middleware {
+	op := options{
+		handler: func(context.Context, any, any) error {
+			return ErrUnknownRequest
+		},
+	}
+	for _, o := range opts {
+		o(&op)
+	}
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (reply any, err error) {
+			startTime := time.Now()
+			defer func() {
+				if rerr := recover(); rerr != nil {
+					buf := make([]byte, 64<<10)
+					n := runtime.Stack(buf, false)
+					buf = buf[:n]
+					log.Context(ctx).Errorw(
+						"kind", "recovery",
+						"operation", operation(ctx),
+						"panic", rerr,
+						"stack", string(buf),
+					)
+					ctx = context.WithValue(ctx, Latency{}, time.Since(startTime).Seconds())
+					err = op.handler(ctx, req, rerr)
+					if err == nil {
+						err = ErrUnknownRequest
+					}
+				}
+			}()
+			return handler(ctx, req)
+		}
+	}
+}
+
+func operation(ctx context.Context) string {
+	if tr, ok := transport.FromServerContext(ctx); ok {
+		return tr.Operation()
+	}
+	if tr, ok := transport.FromClientContext(ctx); ok {
+		return tr.Operation()
+	}
+	return ""
+}
@@ -0,0 +1,48 @@
+package recovery
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"kratos_c/log"
+)
+
+func TestRecovery(t *testing.T) {
+	var buf bytes.Buffer
+	old := log.GetLogger()
+	log.SetLogger(log.NewStdLogger(&buf))
+	defer log.SetLogger(old)
+
+	handler := Recovery()(func(context.Context, any) (any, error) {
+		panic("boom")
+	})
+	_, err := handler(context.Background(), "password=secret")
+	if !errors.Is(err, ErrUnknownRequest) {
+		t.Errorf("got error %v, want %v", err, ErrUnknownRequest)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "boom") || !strings.Contains(out, "stack=") {
+		t.Errorf("log should contain the panic and stack, got %q", out)
+	}
+	if strings.Contains(out, "secret") {
+		t.Errorf("log should not contain the request, got %q", out)
+	}
+}
+
+func TestWithHandler(t *testing.T) {
+	want := errors.New("custom")
+	handler := Recovery(WithHandler(func(ctx context.Context, _, rerr any) error {
+		if _, ok := ctx.Value(Latency{}).(float64); !ok || rerr != "boom" {
+			return nil
+		}
+		return want
+	}))(func(context.Context, any) (any, error) {
+		panic("boom")
+	})
+	if _, err := handler(context.Background(), nil); !errors.Is(err, want) {
+		t.Errorf("got error %v, want %v", err, want)
+	}
+}
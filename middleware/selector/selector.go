@@ -0,0 +1,145 @@
+package selector
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"kratos_c/middleware"
+	"kratos_c/transport"
+)
+
+type (
+	transporter func(ctx context.Context) (transport.Transporter, bool)
+	// MatchFunc 返回 true 时对 operation 应用中间件.
+	MatchFunc func(ctx context.Context, operation string) bool
+)
+
+var (
+	// serverTransporter is get server transport.Transporter from ctx
+	serverTransporter transporter = func(ctx context.Context) (transport.Transporter, bool) {
+		return transport.FromServerContext(ctx)
+	}
+	// clientTransporter is get client transport.Transporter from ctx
+	clientTransporter transporter = func(ctx context.Context) (transport.Transporter, bool) {
+		return transport.FromClientContext(ctx)
+	}
+)
+
+// Builder is a selector builder.
+// Path/Prefix/Regex/Match 之间是或的关系, 命中任意一个就应用中间件, 否则直接调用下一个 handler.
+// 只需要按 operation 挂载中间件时, 也可以使用 grpc.OperationMiddleware 或 http.OperationMiddleware.
+//
+// 注意: ctx 中没有对应的 Transport 时无法得到 operation, 此时总是应用中间件 (fail closed),
+// 避免鉴权等中间件在没有经过 transport 的调用中被跳过.
+type Builder struct {
+	client bool
+
+	prefix []string
+	regex  []string
+	path   []string
+	match  MatchFunc
+
+	ms []middleware.Middleware
+}
+
+// Server selector middleware
+func Server(ms ...middleware.Middleware) *Builder {
+	return &Builder{ms: ms}
+}
+
+// Client selector middleware
+func Client(ms ...middleware.Middleware) *Builder {
+	return &Builder{client: true, ms: ms}
+}
+
+// Prefix is with Builder's prefix, 例如 "/grpc.health.v1.Health/".
+func (b *Builder) Prefix(prefix ...string) *Builder {
+	b.prefix = append(b.prefix, prefix...)
+	return b
+}
+
+// Regex is with Builder's regex, 非法的正则在 Build 时 panic.
+func (b *Builder) Regex(regex ...string) *Builder {
+	b.regex = append(b.regex, regex...)
+	return b
+}
+
+// Path is with Builder's path, 精确匹配 operation.
+func (b *Builder) Path(path ...string) *Builder {
+	b.path = append(b.path, path...)
+	return b
+}
+
+// Match is with Builder's match, 例如对 grpc.health.v1 以外的所有接口鉴权:
+//
+//	selector.Server(jwt.Server(keyFunc)).Match(func(ctx context.Context, operation string) bool {
+//		return !strings.HasPrefix(operation, "/grpc.health.v1.")
+//	}).Build()
+func (b *Builder) Match(fn MatchFunc) *Builder {
+	b.match = fn
+	return b
+}
+
+// Build is Builder's Build, for example: Server(m1, m2).Path("/pkg.Service/Get").Build()
+func (b *Builder) Build() middleware.Middleware {
+	// 复制 prefix, Build 之后继续调用 Prefix 不影响已经生成的中间件
+	s := &selector{
+		prefix: slices.Clone(b.prefix),
+		path:   make(map[string]struct{}, len(b.path)),
+		match:  b.match,
+		ms:     middleware.Chain(b.ms...),
+	}
+	for _, p := range b.path {
+		s.path[p] = struct{}{}
+	}
+	for _, r := range b.regex {
+		s.regex = append(s.regex, regexp.MustCompile(r))
+	}
+	if b.client {
+		s.transporter = clientTransporter
+	} else {
+		s.transporter = serverTransporter
+	}
+	return s.middleware
+}
+
+type selector struct {
+	transporter transporter
+
+	prefix []string
+	regex  []*regexp.Regexp
+	path   map[string]struct{}
+	match  MatchFunc
+
+	ms middleware.Middleware
+}
+
+func (s *selector) middleware(handler middleware.Handler) middleware.Handler {
+	next := s.ms(handler)
+	return func(ctx context.Context, req any) (any, error) {
+		info, ok := s.transporter(ctx)
+		if ok && !s.matches(ctx, info.Operation()) {
+			return handler(ctx, req)
+		}
+		return next(ctx, req)
+	}
+}
+
+func (s *selector) matches(ctx context.Context, operation string) bool {
+	if _, ok := s.path[operation]; ok {
+		return true
+	}
+	for _, prefix := range s.prefix {
+		if strings.HasPrefix(operation, prefix) {
+			return true
+		}
+	}
+	for _, re := range s.regex {
+		if re.MatchString(operation) {
+			return true
+		}
+	}
+	return s.match != nil && s.match(ctx, operation)
+}
@@ -0,0 +1,153 @@
+package selector
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	kerrors "kratos_c/errors"
+	"kratos_c/middleware"
+	"kratos_c/middleware/auth/jwt"
+	"kratos_c/transport"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+type headerCarrier map[string]string
+
+func (h headerCarrier) Get(key string) string { return h[key] }
+func (h headerCarrier) Set(key, value string) { h[key] = value }
+func (h headerCarrier) Add(key, value string) { h[key] = value }
+func (h headerCarrier) Values(key string) []string {
+	if v, ok := h[key]; ok {
+		return []string{v}
+	}
+	return nil
+}
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+type testTransport struct {
+	operation string
+	header    headerCarrier
+}
+
+func (tr *testTransport) Kind() transport.Kind            { return transport.KindGRPC }
+func (tr *testTransport) Endpoint() string                { return "" }
+func (tr *testTransport) Operation() string               { return tr.operation }
+func (tr *testTransport) RequestHeader() transport.Header { return tr.header }
+func (tr *testTransport) ReplyHeader() transport.Header   { return headerCarrier{} }
+
+var errSelected = errors.New("selected")
+
+func selected(handler middleware.Handler) middleware.Handler {
+	return func(context.Context, any) (any, error) {
+		return nil, errSelected
+	}
+}
+
+func handler(context.Context, any) (any, error) { return "ok", nil }
+
+func TestServer(t *testing.T) {
+	m := Server(selected).
+		Path("/pkg.Service/Get").
+		Prefix("/pkg.Admin/").
+		Regex(`^/pkg\.Service/(List|Delete)`).
+		Match(func(_ context.Context, operation string) bool {
+			return strings.HasSuffix(operation, "/Update")
+		}).
+		Build()
+	tests := []struct {
+		operation string
+		selected  bool
+	}{
+		{"/pkg.Service/Get", true},
+		{"/pkg.Service/GetBook", false},
+		{"/pkg.Admin/Reset", true},
+		{"/pkg.Service/ListBooks", true},
+		{"/pkg.Service/Update", true},
+		{"/pkg.Service/Create", false},
+	}
+	for _, tt := range tests {
+		ctx := transport.NewServerContext(context.Background(), &testTransport{operation: tt.operation})
+		_, err := m(handler)(ctx, nil)
+		if got := errors.Is(err, errSelected); got != tt.selected {
+			t.Errorf("%s: selected = %v, want %v", tt.operation, got, tt.selected)
+		}
+	}
+	// 没有 server transport 时无法判断 operation, 总是应用中间件
+	ctx := transport.NewClientContext(context.Background(), &testTransport{operation: "/pkg.Service/Create"})
+	if _, err := m(handler)(ctx, nil); !errors.Is(err, errSelected) {
+		t.Errorf("client context: got error %v, want %v", err, errSelected)
+	}
+	if _, err := m(handler)(context.Background(), nil); !errors.Is(err, errSelected) {
+		t.Errorf("no transport: got error %v, want %v", err, errSelected)
+	}
+}
+
+func TestBuildCopiesBuilder(t *testing.T) {
+	b := Server(selected).Prefix("/pkg.Admin/").Path("/pkg.Service/Get")
+	m := b.Build()
+	// Build 之后修改 builder 不影响已经生成的中间件
+	b.Prefix("/pkg.Service/").Path("/pkg.Service/List")
+	for _, operation := range []string{"/pkg.Service/Create", "/pkg.Service/List"} {
+		ctx := transport.NewServerContext(context.Background(), &testTransport{operation: operation})
+		if _, err := m(handler)(ctx, nil); err != nil {
+			t.Errorf("%s: got error %v, want the middleware to be skipped", operation, err)
+		}
+	}
+}
+
+func TestClient(t *testing.T) {
+	m := Client(selected).Prefix("/pkg.Admin/").Build()
+	tests := []struct {
+		ctx      context.Context
+		selected bool
+	}{
+		{transport.NewClientContext(context.Background(), &testTransport{operation: "/pkg.Admin/Reset"}), true},
+		{transport.NewClientContext(context.Background(), &testTransport{operation: "/pkg.Service/Get"}), false},
+		{transport.NewServerContext(context.Background(), &testTransport{operation: "/pkg.Service/Get"}), true},
+	}
+	for i, tt := range tests {
+		_, err := m(handler)(tt.ctx, nil)
+		if got := errors.Is(err, errSelected); got != tt.selected {
+			t.Errorf("case %d: selected = %v, want %v", i, got, tt.selected)
+		}
+	}
+}
+
+func TestServerSkipsHealthCheck(t *testing.T) {
+	key := []byte("secret")
+	keyFunc := func(*jwtv5.Token) (any, error) { return key, nil }
+	token, err := jwtv5.NewWithClaims(jwtv5.SigningMethodHS256, jwtv5.MapClaims{"sub": "user"}).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := Server(jwt.Server(keyFunc)).Match(func(_ context.Context, operation string) bool {
+		return !strings.HasPrefix(operation, "/grpc.health.v1.")
+	}).Build()
+
+	tests := []struct {
+		operation string
+		header    headerCarrier
+		err       error
+	}{
+		{"/grpc.health.v1.Health/Check", headerCarrier{}, nil},
+		{"/helloworld.Greeter/SayHello", headerCarrier{}, jwt.ErrMissingJwtToken},
+		{"/helloworld.Greeter/SayHello", headerCarrier{"Authorization": "Bearer " + token}, nil},
+	}
+	for _, tt := range tests {
+		ctx := transport.NewServerContext(context.Background(), &testTransport{operation: tt.operation, header: tt.header})
+		_, err := m(handler)(ctx, nil)
+		// jwt 的错误 Code 和 Reason 都相同, 需要比较 Message
+		if (err == nil) != (tt.err == nil) || (err != nil && kerrors.FromError(err).Message != kerrors.FromError(tt.err).Message) {
+			t.Errorf("%s: got error %v, want %v", tt.operation, err, tt.err)
+		}
+	}
+}
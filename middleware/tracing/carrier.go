@@ -0,0 +1,30 @@
+package tracing
+
+import (
+	"kratos_c/transport"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+var _ propagation.TextMapCarrier = (*headerCarrier)(nil)
+
+// headerCarrier 将 transport.Header 适配为 propagation.TextMapCarrier,
+// 使得传播不依赖具体的传输协议.
+type headerCarrier struct {
+	header transport.Header
+}
+
+// Get returns the value associated with the passed key.
+func (hc headerCarrier) Get(key string) string {
+	return hc.header.Get(key)
+}
+
+// Set stores the key-value pair.
+func (hc headerCarrier) Set(key string, value string) {
+	hc.header.Set(key, value)
+}
+
+// Keys lists the keys stored in this carrier.
+func (hc headerCarrier) Keys() []string {
+	return hc.header.Keys()
+}
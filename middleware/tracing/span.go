@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kratos_c/selector"
+	"kratos_c/transport"
+	khttp "kratos_c/transport/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/peer"
+)
+
+func setClientSpan(ctx context.Context, span trace.Span, tr transport.Transporter) {
+	attrs := []attribute.KeyValue{}
+	var remote string
+	switch tr.Kind() {
+	case transport.KindHTTP:
+		if ht, ok := tr.(khttp.Transporter); ok {
+			attrs = append(attrs, httpAttributes(ht.Request())...)
+			remote = ht.Request().Host
+		}
+	case transport.KindGRPC:
+		attrs = append(attrs, grpcAttributes(tr.Operation())...)
+		// 通过服务发现调用时, 只有选中节点后才知道真实的对端地址
+		if p, ok := selector.FromPeerContext(ctx); ok && p.Node != nil {
+			remote = p.Node.Address()
+		}
+	}
+	if remote == "" {
+		remote = tr.Endpoint()
+	}
+	attrs = append(attrs, peerAttributes(remote)...)
+	span.SetAttributes(attrs...)
+}
+
+func setServerSpan(ctx context.Context, span trace.Span, tr transport.Transporter) {
+	attrs := []attribute.KeyValue{}
+	var remote string
+	switch tr.Kind() {
+	case transport.KindHTTP:
+		if ht, ok := tr.(khttp.Transporter); ok {
+			attrs = append(attrs, httpAttributes(ht.Request())...)
+			remote = ht.Request().RemoteAddr
+		}
+	case transport.KindGRPC:
+		attrs = append(attrs, grpcAttributes(tr.Operation())...)
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			remote = p.Addr.String()
+		}
+	}
+	attrs = append(attrs, peerAttributes(remote)...)
+	span.SetAttributes(attrs...)
+}
+
+func httpAttributes(req *http.Request) []attribute.KeyValue {
+	if req == nil {
+		return nil
+	}
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.URLPath(req.URL.Path),
+	}
+	if ua := req.UserAgent(); ua != "" {
+		attrs = append(attrs, semconv.UserAgentOriginal(ua))
+	}
+	return attrs
+}
+
+// grpcAttributes 从 /pkg.Service/Method 中解析出服务名与方法名
+func grpcAttributes(operation string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{semconv.RPCSystemGRPC}
+	service, method, ok := strings.Cut(strings.TrimPrefix(operation, "/"), "/")
+	if ok {
+		attrs = append(attrs, semconv.RPCService(service), semconv.RPCMethod(method))
+	}
+	return attrs
+}
+
+func peerAttributes(addr string) []attribute.KeyValue {
+	if addr == "" {
+		return nil
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return []attribute.KeyValue{semconv.NetworkPeerAddress(addr)}
+	}
+	attrs := []attribute.KeyValue{semconv.NetworkPeerAddress(host)}
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.NetworkPeerPort(p))
+	}
+	return attrs
+}
@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"context"
+
+	"kratos_c/errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer is otel span tracer
+type Tracer struct {
+	tracer trace.Tracer
+	kind   trace.SpanKind
+	opt    *options
+}
+
+// NewTracer create tracer instance
+func NewTracer(kind trace.SpanKind, opts ...Option) *Tracer {
+	op := options{
+		propagator: propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+		tracerName: defaultTracerName,
+	}
+	for _, o := range opts {
+		o(&op)
+	}
+	if op.tracerProvider == nil {
+		op.tracerProvider = otel.GetTracerProvider()
+	}
+	switch kind {
+	case trace.SpanKindClient, trace.SpanKindServer:
+		return &Tracer{tracer: op.tracerProvider.Tracer(op.tracerName), kind: kind, opt: &op}
+	default:
+		panic("unsupported span kind: " + kind.String())
+	}
+}
+
+// Start start tracing span
+// 服务端先从 carrier 中提取上游的 trace context, 客户端在创建 span 后注入 carrier.
+func (t *Tracer) Start(ctx context.Context, operation string, carrier propagation.TextMapCarrier) (context.Context, trace.Span) {
+	if t.kind == trace.SpanKindServer {
+		ctx = t.opt.propagator.Extract(ctx, carrier)
+	}
+	ctx, span := t.tracer.Start(ctx,
+		operation,
+		trace.WithSpanKind(t.kind),
+	)
+	if t.kind == trace.SpanKindClient {
+		t.opt.propagator.Inject(ctx, carrier)
+	}
+	return ctx, span
+}
+
+// End finish tracing span
+func (t *Tracer) End(_ context.Context, span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		if e := errors.FromError(err); e != nil {
+			span.SetAttributes(
+				attribute.Int("rpc.status_code", int(e.Code)),
+				attribute.String("rpc.status_reason", e.Reason),
+			)
+		}
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, "OK")
+	}
+	span.End()
+}
@@ -0,0 +1,166 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"kratos_c/transport"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	spanID      = "00f067aa0ba902b7"
+	traceparent = "00-" + traceID + "-" + spanID + "-01"
+)
+
+type testHeader map[string]string
+
+func (h testHeader) Get(key string) string { return h[key] }
+func (h testHeader) Set(key, value string) { h[key] = value }
+func (h testHeader) Add(key, value string) { h[key] = value }
+func (h testHeader) Values(key string) []string {
+	if v, ok := h[key]; ok {
+		return []string{v}
+	}
+	return nil
+}
+
+func (h testHeader) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+type testTransport struct {
+	kind   transport.Kind
+	header testHeader
+}
+
+func (tr *testTransport) Kind() transport.Kind            { return tr.kind }
+func (tr *testTransport) Endpoint() string                { return "" }
+func (tr *testTransport) Operation() string               { return "/helloworld.Greeter/SayHello" }
+func (tr *testTransport) RequestHeader() transport.Header { return tr.header }
+func (tr *testTransport) ReplyHeader() transport.Header   { return testHeader{} }
+
+// noop 的 TracerProvider 不记录 span, 但新建的 span 沿用父 span 的 SpanContext,
+// 足以验证传播是否正确.
+func provider() Option {
+	return WithTracerProvider(noop.NewTracerProvider())
+}
+
+func remoteSpanContext(t *testing.T) trace.SpanContext {
+	t.Helper()
+	tid, err := trace.TraceIDFromHex(traceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sid, err := trace.SpanIDFromHex(spanID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+}
+
+func TestServerExtract(t *testing.T) {
+	tr := &testTransport{kind: transport.KindGRPC, header: testHeader{
+		"traceparent": traceparent,
+		"baggage":     "user=kratos",
+	}}
+	ctx := transport.NewServerContext(context.Background(), tr)
+	_, err := Server(provider())(func(ctx context.Context, _ any) (any, error) {
+		sc := trace.SpanContextFromContext(ctx)
+		if !sc.Equal(remoteSpanContext(t)) {
+			t.Errorf("got span context %+v, want the one from traceparent", sc)
+		}
+		if got := TraceID()(ctx); got != traceID {
+			t.Errorf("TraceID: got %v, want %s", got, traceID)
+		}
+		if got := SpanID()(ctx); got != spanID {
+			t.Errorf("SpanID: got %v, want %s", got, spanID)
+		}
+		if got := baggage.FromContext(ctx).Member("user").Value(); got != "kratos" {
+			t.Errorf("got baggage user %q, want %q", got, "kratos")
+		}
+		return nil, nil
+	})(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerWithoutTraceparent(t *testing.T) {
+	ctx := transport.NewServerContext(context.Background(), &testTransport{kind: transport.KindGRPC, header: testHeader{}})
+	_, _ = Server(provider())(func(ctx context.Context, _ any) (any, error) {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			t.Errorf("got span context %+v, want invalid", sc)
+		}
+		if got := TraceID()(ctx); got != "" {
+			t.Errorf("TraceID: got %v, want empty", got)
+		}
+		return nil, nil
+	})(ctx, nil)
+}
+
+func TestClientInject(t *testing.T) {
+	header := testHeader{}
+	ctx := trace.ContextWithSpanContext(context.Background(), remoteSpanContext(t))
+	ctx = transport.NewClientContext(ctx, &testTransport{kind: transport.KindGRPC, header: header})
+	_, err := Client(provider())(func(context.Context, any) (any, error) {
+		// 调用下游之前已经注入
+		if got := header["traceparent"]; got != traceparent {
+			t.Errorf("got traceparent %q, want %q", got, traceparent)
+		}
+		return nil, nil
+	})(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 没有 trace context 时不注入
+	header = testHeader{}
+	ctx = transport.NewClientContext(context.Background(), &testTransport{kind: transport.KindGRPC, header: header})
+	_, _ = Client(provider())(func(context.Context, any) (any, error) { return nil, nil })(ctx, nil)
+	if got, ok := header["traceparent"]; ok {
+		t.Errorf("got traceparent %q, want none", got)
+	}
+}
+
+func TestDirection(t *testing.T) {
+	parent := trace.ContextWithSpanContext(context.Background(), remoteSpanContext(t))
+
+	// 服务端中间件不向请求头注入
+	header := testHeader{}
+	ctx := transport.NewServerContext(parent, &testTransport{kind: transport.KindGRPC, header: header})
+	_, _ = Server(provider())(func(context.Context, any) (any, error) { return nil, nil })(ctx, nil)
+	if got, ok := header["traceparent"]; ok {
+		t.Errorf("server: got traceparent %q, want none", got)
+	}
+
+	// 客户端中间件只读取 client transport
+	header = testHeader{}
+	ctx = transport.NewServerContext(parent, &testTransport{kind: transport.KindGRPC, header: header})
+	_, _ = Client(provider())(func(context.Context, any) (any, error) { return nil, nil })(ctx, nil)
+	if got, ok := header["traceparent"]; ok {
+		t.Errorf("client: got traceparent %q, want none", got)
+	}
+}
+
+func TestNewTracerKind(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("got no panic for an internal span kind")
+		}
+	}()
+	NewTracer(trace.SpanKindInternal)
+}
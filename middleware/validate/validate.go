@@ -0,0 +1,58 @@
+package validate
+
+import (
+	"context"
+
+	"kratos_c/errors"
+	"kratos_c/middleware"
+)
+
+type validator interface {
+	Validate() error
+}
+
+// violation 是 protoc-gen-validate 生成的字段校验错误
+type violation interface {
+	Field() string
+	Reason() string
+}
+
+// multiError 是 protoc-gen-validate ValidateAll 返回的错误集合
+type multiError interface {
+	AllErrors() []error
+}
+
+// Validator is a validator middleware.
+// 请求实现了 Validate() error 时先进行校验, 失败时返回 BadRequest,
+// 违反规则的字段及原因放在错误的 Metadata 中.
+func Validator() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (reply any, err error) {
+			if v, ok := req.(validator); ok {
+				if err := v.Validate(); err != nil {
+					return nil, errors.BadRequest("VALIDATOR", err.Error()).
+						WithMetadata(violations(err)).
+						WithCause(err)
+				}
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+// violations 提取字段校验错误, key 为字段名, value 为原因
+func violations(err error) map[string]string {
+	errs := []error{err}
+	var me multiError
+	if errors.As(err, &me) {
+		errs = me.AllErrors()
+	}
+	md := make(map[string]string, len(errs))
+	for _, e := range errs {
+		var v violation
+		if errors.As(e, &v) {
+			md[v.Field()] = v.Reason()
+		}
+	}
+	return md
+}
@@ -0,0 +1,93 @@
+package validate
+
+import (
+	"context"
+	stderrors "errors"
+	"maps"
+	"strings"
+	"testing"
+
+	"kratos_c/errors"
+)
+
+// fieldError 模拟 protoc-gen-validate 生成的 XxxValidationError
+type fieldError struct {
+	field  string
+	reason string
+}
+
+func (e fieldError) Field() string  { return e.field }
+func (e fieldError) Reason() string { return e.reason }
+func (e fieldError) Error() string  { return "invalid " + e.field + ": " + e.reason }
+
+// fieldMultiError 模拟 protoc-gen-validate 生成的 XxxMultiError
+type fieldMultiError []error
+
+func (m fieldMultiError) AllErrors() []error { return m }
+func (m fieldMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, e := range m {
+		msgs = append(msgs, e.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// request 的 Validate 返回 err
+type request struct {
+	err error
+}
+
+func (r request) Validate() error { return r.err }
+
+func TestValidator(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		md   map[string]string
+	}{
+		{"violation", fieldError{"name", "value length must be at least 1 runes"}, map[string]string{
+			"name": "value length must be at least 1 runes",
+		}},
+		{"multi error", &fieldMultiError{
+			fieldError{"name", "value length must be at least 1 runes"},
+			fieldError{"age", "value must be greater than 0"},
+			stderrors.New("not a violation"),
+		}, map[string]string{
+			"name": "value length must be at least 1 runes",
+			"age":  "value must be greater than 0",
+		}},
+		{"plain error", stderrors.New("invalid request"), map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Validator()(func(context.Context, any) (any, error) {
+				t.Error("got the handler called with an invalid request")
+				return nil, nil
+			})(context.Background(), request{tt.err})
+			e := errors.FromError(err)
+			if e.Code != 400 || e.Reason != "VALIDATOR" {
+				t.Errorf("got code %d reason %q, want 400 %q", e.Code, e.Reason, "VALIDATOR")
+			}
+			if e.Message != tt.err.Error() {
+				t.Errorf("got message %q, want %q", e.Message, tt.err.Error())
+			}
+			if !maps.Equal(e.Metadata, tt.md) {
+				t.Errorf("got metadata %v, want %v", e.Metadata, tt.md)
+			}
+			if !stderrors.Is(err, tt.err) {
+				t.Errorf("got error %v, want it to wrap %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidatorPass(t *testing.T) {
+	handler := func(context.Context, any) (any, error) { return "reply", nil }
+	// 校验通过以及没有实现 Validate 的请求都交给 handler
+	for _, req := range []any{request{}, "not a validator", nil} {
+		reply, err := Validator()(handler)(context.Background(), req)
+		if err != nil || reply != "reply" {
+			t.Errorf("request %#v: got reply %v, error %v", req, reply, err)
+		}
+	}
+}
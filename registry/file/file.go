@@ -0,0 +1,261 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"kratos_c/registry"
+)
+
+var (
+	_ registry.Registrar = (*Registry)(nil)
+	_ registry.Discovery = (*Registry)(nil)
+)
+
+// ErrInvalidID 实例 ID 为空或以 "." 开头, 以 "." 开头的文件是临时文件, 读取时会被忽略.
+var ErrInvalidID = errors.New("file registry: invalid instance id")
+
+const (
+	fileExt = ".json"
+
+	defaultInterval = time.Second
+	defaultTTL      = 30 * time.Second
+)
+
+// Option is file registry option.
+type Option func(o *options)
+
+type options struct {
+	interval time.Duration
+	ttl      time.Duration
+	perm     os.FileMode
+}
+
+// Interval with watcher polling interval, 小于等于 0 时使用默认值 1s.
+func Interval(interval time.Duration) Option {
+	return func(o *options) { o.interval = interval }
+}
+
+// TTL with instance ttl, 默认 30s.
+// Register 之后会每隔 ttl/3 更新一次文件的修改时间, 超过 ttl 没有更新的文件 (例如进程崩溃后留下的) 会被忽略.
+// 判断依赖各节点的时钟, ttl 需要明显大于节点之间的时钟偏差; 小于等于 0 表示永不过期.
+func TTL(ttl time.Duration) Option {
+	return func(o *options) { o.ttl = ttl }
+}
+
+// FileMode with instance file permission.
+func FileMode(perm os.FileMode) Option {
+	return func(o *options) { o.perm = perm }
+}
+
+// Registry 以目录作为注册中心, 每个实例对应一个 json 文件, 适用于只有共享存储(如 NFS)的部署环境.
+type Registry struct {
+	dir  string
+	opts options
+
+	mu sync.Mutex
+	// heartbeats 保存本进程注册的实例的心跳, 以实例 ID 为 key
+	heartbeats map[string]context.CancelFunc
+
+	cacheMu sync.Mutex
+	// cache 保存已经解析的实例文件, 以文件名为 key, 文件没有被替换时不再重复读取
+	cache map[string]cached
+}
+
+type cached struct {
+	info os.FileInfo
+	// ins 为 nil 表示文件无法解析
+	ins *registry.ServiceInstance
+}
+
+// New creates a file registry, dir 不存在时会自动创建.
+func New(dir string, opts ...Option) (*Registry, error) {
+	o := options{
+		interval: defaultInterval,
+		ttl:      defaultTTL,
+		perm:     0o644,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.interval <= 0 {
+		o.interval = defaultInterval
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Registry{
+		dir:        dir,
+		opts:       o,
+		heartbeats: make(map[string]context.CancelFunc),
+		cache:      make(map[string]cached),
+	}, nil
+}
+
+// Register 将实例写入文件, 先写临时文件再 rename, 保证读取方不会看到写了一半的内容.
+func (r *Registry) Register(_ context.Context, service *registry.ServiceInstance) error {
+	if service.ID == "" || strings.HasPrefix(service.ID, ".") {
+		return ErrInvalidID
+	}
+	data, err := json.Marshal(service)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(r.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	if _, err = f.Write(data); err == nil {
+		err = f.Chmod(r.opts.perm)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, r.filename(service.ID))
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if r.opts.ttl > 0 {
+		r.heartbeat(service.ID)
+	}
+	return nil
+}
+
+// heartbeat 定期更新实例文件的修改时间, 重复注册同一个实例时替换之前的心跳.
+func (r *Registry) heartbeat(id string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.mu.Lock()
+	if stop, ok := r.heartbeats[id]; ok {
+		stop()
+	}
+	r.heartbeats[id] = cancel
+	r.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(r.opts.ttl / 3)
+		defer ticker.Stop()
+		filename := r.filename(id)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				now := time.Now()
+				_ = os.Chtimes(filename, now, now)
+			}
+		}
+	}()
+}
+
+// Deregister 删除实例文件.
+func (r *Registry) Deregister(_ context.Context, service *registry.ServiceInstance) error {
+	r.mu.Lock()
+	if stop, ok := r.heartbeats[service.ID]; ok {
+		stop()
+		delete(r.heartbeats, service.ID)
+	}
+	r.mu.Unlock()
+	err := os.Remove(r.filename(service.ID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Close 停止本进程注册的实例的心跳, 不删除实例文件, 没有 Deregister 的实例会在 ttl 后过期.
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, stop := range r.heartbeats {
+		stop()
+		delete(r.heartbeats, id)
+	}
+	return nil
+}
+
+// GetService return the service instances in the directory according to the service name.
+func (r *Registry) GetService(_ context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	return r.load(serviceName)
+}
+
+// Watch creates a watcher according to the service name.
+func (r *Registry) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
+	return newWatcher(ctx, r, serviceName), nil
+}
+
+func (r *Registry) filename(id string) string {
+	return filepath.Join(r.dir, url.PathEscape(id)+fileExt)
+}
+
+// load 读取目录中属于 serviceName 的实例.
+// Register 总是通过 rename 替换文件, 因此文件没有被替换(同一个文件且大小不变)时直接使用缓存, 心跳只会修改文件的修改时间.
+func (r *Registry) load(serviceName string) ([]*registry.ServiceInstance, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, err
+	}
+	r.cacheMu.Lock()
+	defer r.cacheMu.Unlock()
+	services := make([]*registry.ServiceInstance, 0)
+	seen := make(map[string]struct{}, len(entries))
+	now := time.Now()
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), fileExt) || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		info, err := e.Info()
+		// 文件可能在读取目录后被删除, 或者超过 ttl 没有心跳
+		if err != nil || r.opts.ttl > 0 && now.Sub(info.ModTime()) > r.opts.ttl {
+			continue
+		}
+		seen[e.Name()] = struct{}{}
+		c, ok := r.cache[e.Name()]
+		if !ok || !os.SameFile(c.info, info) || c.info.Size() != info.Size() {
+			data, err := os.ReadFile(filepath.Join(r.dir, e.Name()))
+			if err != nil {
+				// 文件可能在读取目录后被删除
+				if errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+				return nil, err
+			}
+			c = cached{info: info, ins: new(registry.ServiceInstance)}
+			// 忽略无法解析的文件, 避免一个坏文件影响整个服务
+			if err = json.Unmarshal(data, c.ins); err != nil {
+				c.ins = nil
+			}
+			r.cache[e.Name()] = c
+		}
+		if c.ins != nil && c.ins.Name == serviceName {
+			services = append(services, clone(c.ins))
+		}
+	}
+	for name := range r.cache {
+		if _, ok := seen[name]; !ok {
+			delete(r.cache, name)
+		}
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].ID < services[j].ID })
+	return services, nil
+}
+
+func clone(in *registry.ServiceInstance) *registry.ServiceInstance {
+	out := *in
+	out.Metadata = maps.Clone(in.Metadata)
+	out.Endpoints = slices.Clone(in.Endpoints)
+	return &out
+}
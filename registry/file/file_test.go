@@ -0,0 +1,252 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"kratos_c/registry"
+)
+
+func instance(id, name string) *registry.ServiceInstance {
+	return &registry.ServiceInstance{
+		ID:        id,
+		Name:      name,
+		Version:   "v1",
+		Metadata:  map[string]string{"zone": "a"},
+		Endpoints: []string{"grpc://127.0.0.1:9000"},
+	}
+}
+
+func ids(services []*registry.ServiceInstance) []string {
+	s := make([]string, 0, len(services))
+	for _, ins := range services {
+		s = append(s, ins.ID)
+	}
+	return s
+}
+
+func newRegistry(t *testing.T, opts ...Option) *Registry {
+	t.Helper()
+	r, err := New(t.TempDir(), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = r.Close() })
+	return r
+}
+
+func TestRegister(t *testing.T) {
+	ctx := context.Background()
+	r := newRegistry(t)
+	for _, ins := range []*registry.ServiceInstance{
+		instance("2", "helloworld"),
+		instance("1", "helloworld"),
+		instance("3", "other"),
+		instance("a/b", "helloworld"),
+	} {
+		if err := r.Register(ctx, ins); err != nil {
+			t.Fatal(err)
+		}
+	}
+	services, err := r.GetService(ctx, "helloworld")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(services), []string{"1", "2", "a/b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got instances %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(services[0], instance("1", "helloworld")) {
+		t.Errorf("got instance %+v", services[0])
+	}
+
+	if err = r.Deregister(ctx, instance("2", "helloworld")); err != nil {
+		t.Fatal(err)
+	}
+	// 注销不存在的实例不报错
+	if err = r.Deregister(ctx, instance("2", "helloworld")); err != nil {
+		t.Fatal(err)
+	}
+	services, _ = r.GetService(ctx, "helloworld")
+	if got, want := ids(services), []string{"1", "a/b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after deregister: got instances %v, want %v", got, want)
+	}
+}
+
+func TestRegisterInvalidID(t *testing.T) {
+	r := newRegistry(t)
+	for _, id := range []string{"", ".hidden", ".tmp-1"} {
+		if err := r.Register(context.Background(), instance(id, "helloworld")); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("id %q: got error %v, want %v", id, err, ErrInvalidID)
+		}
+	}
+}
+
+func TestLoadIgnoresInvalidFiles(t *testing.T) {
+	r := newRegistry(t)
+	if err := r.Register(context.Background(), instance("1", "helloworld")); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string]string{
+		"broken.json":  "{",
+		".tmp-1.json":  `{"id":"tmp","name":"helloworld"}`,
+		"readme.txt":   `{"id":"txt","name":"helloworld"}`,
+		"empty.json":   "",
+		"another.json": `{"id":"2","name":"other"}`,
+	} {
+		if err := os.WriteFile(filepath.Join(r.dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	services, err := r.GetService(context.Background(), "helloworld")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(services), []string{"1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got instances %v, want %v", got, want)
+	}
+}
+
+func TestLoadCache(t *testing.T) {
+	ctx := context.Background()
+	r := newRegistry(t)
+	ins := instance("1", "helloworld")
+	if err := r.Register(ctx, ins); err != nil {
+		t.Fatal(err)
+	}
+	services, _ := r.GetService(ctx, "helloworld")
+	// 返回的实例是副本, 修改不影响缓存
+	services[0].Metadata["zone"] = "b"
+	services[0].Endpoints[0] = "changed"
+	services, _ = r.GetService(ctx, "helloworld")
+	if !reflect.DeepEqual(services[0], ins) {
+		t.Errorf("got instance %+v, want %+v", services[0], ins)
+	}
+	if len(r.cache) != 1 {
+		t.Errorf("got %d cached files, want 1", len(r.cache))
+	}
+
+	// 重新注册会替换文件, 需要重新读取
+	ins.Metadata = map[string]string{"zone": "b"}
+	if err := r.Register(ctx, ins); err != nil {
+		t.Fatal(err)
+	}
+	services, _ = r.GetService(ctx, "helloworld")
+	if got := services[0].Metadata["zone"]; got != "b" {
+		t.Errorf("got zone %q after re-register, want %q", got, "b")
+	}
+
+	// 只修改时间时使用缓存
+	now := time.Now()
+	if err := os.Chtimes(r.filename("1"), now, now); err != nil {
+		t.Fatal(err)
+	}
+	before := r.cache[filepath.Base(r.filename("1"))]
+	if _, err := r.GetService(ctx, "helloworld"); err != nil {
+		t.Fatal(err)
+	}
+	if after := r.cache[filepath.Base(r.filename("1"))]; after.ins != before.ins {
+		t.Error("file is read again after only the modification time changed")
+	}
+
+	// 删除的文件从缓存中移除
+	if err := r.Deregister(ctx, ins); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetService(ctx, "helloworld"); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.cache) != 0 {
+		t.Errorf("got %d cached files after deregister, want 0", len(r.cache))
+	}
+}
+
+func TestTTL(t *testing.T) {
+	ctx := context.Background()
+	ttl := 300 * time.Millisecond
+	r := newRegistry(t, TTL(ttl))
+	if err := r.Register(ctx, instance("1", "helloworld")); err != nil {
+		t.Fatal(err)
+	}
+	// 心跳会在 ttl 内更新修改时间
+	time.Sleep(ttl * 3 / 2)
+	services, _ := r.GetService(ctx, "helloworld")
+	if got, want := ids(services), []string{"1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("with heartbeat: got instances %v, want %v", got, want)
+	}
+
+	// Close 之后没有心跳, 超过 ttl 的实例被忽略
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.heartbeats) != 0 {
+		t.Errorf("got %d heartbeats after Close, want 0", len(r.heartbeats))
+	}
+	past := time.Now().Add(-2 * ttl)
+	if err := os.Chtimes(r.filename("1"), past, past); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(ttl / 2)
+	services, _ = r.GetService(ctx, "helloworld")
+	if len(services) != 0 {
+		t.Errorf("after Close: got instances %v, want none", ids(services))
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	ctx := context.Background()
+	r := newRegistry(t, Interval(10*time.Millisecond))
+	w, err := r.Watch(ctx, "helloworld")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := func(want ...string) {
+		t.Helper()
+		services, err := w.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(services); !reflect.DeepEqual(got, want) {
+			t.Errorf("got instances %v, want %v", got, want)
+		}
+	}
+
+	if err = r.Register(ctx, instance("1", "helloworld")); err != nil {
+		t.Fatal(err)
+	}
+	next("1")
+	// 其他服务的变化不会通知
+	if err = r.Register(ctx, instance("3", "other")); err != nil {
+		t.Fatal(err)
+	}
+	if err = r.Register(ctx, instance("2", "helloworld")); err != nil {
+		t.Fatal(err)
+	}
+	next("1", "2")
+	if err = r.Deregister(ctx, instance("1", "helloworld")); err != nil {
+		t.Fatal(err)
+	}
+	next("2")
+
+	// 已有实例时第一次 Next 直接返回
+	w2, err := r.Watch(ctx, "helloworld")
+	if err != nil {
+		t.Fatal(err)
+	}
+	services, err := w2.Next()
+	if err != nil || !reflect.DeepEqual(ids(services), []string{"2"}) {
+		t.Errorf("first Next: got instances %v, error %v", ids(services), err)
+	}
+
+	if err = w.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Next(); !errors.Is(err, context.Canceled) {
+		t.Errorf("after Stop: got error %v, want %v", err, context.Canceled)
+	}
+	_ = w2.Stop()
+}
@@ -0,0 +1,69 @@
+package file
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"kratos_c/registry"
+)
+
+var _ registry.Watcher = (*watcher)(nil)
+
+// watcher 定期轮询目录, 实例列表发生变化时返回.
+type watcher struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	r           *Registry
+	serviceName string
+	ticker      *time.Ticker
+	first       bool
+	last        []*registry.ServiceInstance
+}
+
+func newWatcher(ctx context.Context, r *Registry, serviceName string) *watcher {
+	w := &watcher{
+		r:           r,
+		serviceName: serviceName,
+		ticker:      time.NewTicker(r.opts.interval),
+		first:       true,
+	}
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	return w
+}
+
+func (w *watcher) Next() ([]*registry.ServiceInstance, error) {
+	if w.first {
+		w.first = false
+		services, err := w.r.load(w.serviceName)
+		if err != nil {
+			return nil, err
+		}
+		w.last = services
+		if len(services) > 0 {
+			return services, nil
+		}
+	}
+	for {
+		select {
+		case <-w.ctx.Done():
+			return nil, w.ctx.Err()
+		case <-w.ticker.C:
+		}
+		services, err := w.r.load(w.serviceName)
+		if err != nil {
+			return nil, err
+		}
+		if reflect.DeepEqual(services, w.last) {
+			continue
+		}
+		w.last = services
+		return services, nil
+	}
+}
+
+func (w *watcher) Stop() error {
+	w.cancel()
+	w.ticker.Stop()
+	return nil
+}
@@ -0,0 +1,218 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"kratos_c/registry"
+)
+
+var (
+	_ registry.Registrar = (*Registry)(nil)
+	_ registry.Discovery = (*Registry)(nil)
+)
+
+// Option is memory registry option.
+type Option func(o *options)
+
+type options struct {
+	ctx context.Context
+	ttl time.Duration
+}
+
+// Context with registry context.
+func Context(ctx context.Context) Option {
+	return func(o *options) { o.ctx = ctx }
+}
+
+// TTL with instance ttl, 实例在 ttl 内没有再次 Register 就会被剔除, 0 表示永不过期.
+func TTL(ttl time.Duration) Option {
+	return func(o *options) { o.ttl = ttl }
+}
+
+type instance struct {
+	ins      *registry.ServiceInstance
+	expireAt time.Time
+}
+
+// Registry is an in-process registry, 适用于测试和单进程部署.
+type Registry struct {
+	opts   options
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	lock     sync.RWMutex
+	services map[string]map[string]*instance
+	watchers map[string]map[*watcher]struct{}
+}
+
+// New creates a memory registry.
+func New(opts ...Option) *Registry {
+	o := options{
+		ctx: context.Background(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	ctx, cancel := context.WithCancel(o.ctx)
+	r := &Registry{
+		opts:     o,
+		ctx:      ctx,
+		cancel:   cancel,
+		services: make(map[string]map[string]*instance),
+		watchers: make(map[string]map[*watcher]struct{}),
+	}
+	if o.ttl > 0 {
+		go r.evict()
+	}
+	return r
+}
+
+// Register 注册实例, 重复注册同一个实例即为续约(心跳).
+func (r *Registry) Register(_ context.Context, service *registry.ServiceInstance) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	ins, ok := r.services[service.Name]
+	if !ok {
+		ins = make(map[string]*instance)
+		r.services[service.Name] = ins
+	}
+	var expireAt time.Time
+	if r.opts.ttl > 0 {
+		expireAt = time.Now().Add(r.opts.ttl)
+	}
+	ins[service.ID] = &instance{ins: clone(service), expireAt: expireAt}
+	r.broadcast(service.Name)
+	return nil
+}
+
+// Deregister 注销实例.
+func (r *Registry) Deregister(_ context.Context, service *registry.ServiceInstance) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	ins, ok := r.services[service.Name]
+	if !ok {
+		return nil
+	}
+	if _, ok = ins[service.ID]; !ok {
+		return nil
+	}
+	delete(ins, service.ID)
+	if len(ins) == 0 {
+		delete(r.services, service.Name)
+	}
+	r.broadcast(service.Name)
+	return nil
+}
+
+// GetService return the service instances in memory according to the service name.
+func (r *Registry) GetService(_ context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.snapshot(serviceName), nil
+}
+
+// Watch creates a watcher according to the service name.
+func (r *Registry) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	w := newWatcher(ctx, r, serviceName)
+	ws, ok := r.watchers[serviceName]
+	if !ok {
+		ws = make(map[*watcher]struct{})
+		r.watchers[serviceName] = ws
+	}
+	ws[w] = struct{}{}
+	// 第一次监听且实例列表不为空时, 直接返回当前实例
+	if s := r.snapshot(serviceName); len(s) > 0 {
+		w.notify(s)
+	}
+	return w, nil
+}
+
+// Close 停止过期剔除.
+func (r *Registry) Close() error {
+	r.cancel()
+	return nil
+}
+
+func (r *Registry) remove(w *watcher) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	ws, ok := r.watchers[w.serviceName]
+	if !ok {
+		return
+	}
+	delete(ws, w)
+	if len(ws) == 0 {
+		delete(r.watchers, w.serviceName)
+	}
+}
+
+// broadcast 调用方需持有锁
+func (r *Registry) broadcast(serviceName string) {
+	ws := r.watchers[serviceName]
+	if len(ws) == 0 {
+		return
+	}
+	s := r.snapshot(serviceName)
+	for w := range ws {
+		w.notify(s)
+	}
+}
+
+// snapshot 调用方需持有锁
+func (r *Registry) snapshot(serviceName string) []*registry.ServiceInstance {
+	ins := r.services[serviceName]
+	s := make([]*registry.ServiceInstance, 0, len(ins))
+	for _, in := range ins {
+		s = append(s, clone(in.ins))
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i].ID < s[j].ID })
+	return s
+}
+
+func (r *Registry) evict() {
+	ticker := time.NewTicker(r.opts.ttl / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case now := <-ticker.C:
+			r.lock.Lock()
+			for name, ins := range r.services {
+				changed := false
+				for id, in := range ins {
+					if now.After(in.expireAt) {
+						delete(ins, id)
+						changed = true
+					}
+				}
+				if len(ins) == 0 {
+					delete(r.services, name)
+				}
+				if changed {
+					r.broadcast(name)
+				}
+			}
+			r.lock.Unlock()
+		}
+	}
+}
+
+func clone(in *registry.ServiceInstance) *registry.ServiceInstance {
+	out := *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for k, v := range in.Metadata {
+			out.Metadata[k] = v
+		}
+	}
+	if in.Endpoints != nil {
+		out.Endpoints = append([]string(nil), in.Endpoints...)
+	}
+	return &out
+}
@@ -0,0 +1,234 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"kratos_c/registry"
+)
+
+func newInstance(id, name string) *registry.ServiceInstance {
+	return &registry.ServiceInstance{
+		ID:        id,
+		Name:      name,
+		Version:   "v1",
+		Metadata:  map[string]string{"zone": "a"},
+		Endpoints: []string{"grpc://127.0.0.1:9000"},
+	}
+}
+
+func ids(services []*registry.ServiceInstance) []string {
+	s := make([]string, 0, len(services))
+	for _, ins := range services {
+		s = append(s, ins.ID)
+	}
+	return s
+}
+
+func TestRegister(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	defer r.Close()
+	for _, ins := range []*registry.ServiceInstance{
+		newInstance("2", "helloworld"),
+		newInstance("1", "helloworld"),
+		newInstance("3", "other"),
+	} {
+		if err := r.Register(ctx, ins); err != nil {
+			t.Fatal(err)
+		}
+	}
+	services, err := r.GetService(ctx, "helloworld")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(services), []string{"1", "2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got instances %v, want %v", got, want)
+	}
+
+	// 重复注册替换已有实例
+	updated := newInstance("1", "helloworld")
+	updated.Version = "v2"
+	if err = r.Register(ctx, updated); err != nil {
+		t.Fatal(err)
+	}
+	services, _ = r.GetService(ctx, "helloworld")
+	if len(services) != 2 || services[0].Version != "v2" {
+		t.Errorf("after re-register: got instances %+v", services)
+	}
+
+	if services, _ = r.GetService(ctx, "unknown"); len(services) != 0 {
+		t.Errorf("unknown service: got instances %v, want none", ids(services))
+	}
+}
+
+func TestDeregister(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	defer r.Close()
+	_ = r.Register(ctx, newInstance("1", "helloworld"))
+	_ = r.Register(ctx, newInstance("2", "helloworld"))
+	if err := r.Deregister(ctx, newInstance("1", "helloworld")); err != nil {
+		t.Fatal(err)
+	}
+	services, _ := r.GetService(ctx, "helloworld")
+	if got, want := ids(services), []string{"2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got instances %v, want %v", got, want)
+	}
+	// 注销不存在的实例和服务不报错
+	for _, ins := range []*registry.ServiceInstance{newInstance("1", "helloworld"), newInstance("1", "unknown")} {
+		if err := r.Deregister(ctx, ins); err != nil {
+			t.Errorf("deregister %s/%s: got error %v", ins.Name, ins.ID, err)
+		}
+	}
+	_ = r.Deregister(ctx, newInstance("2", "helloworld"))
+	if services, _ = r.GetService(ctx, "helloworld"); len(services) != 0 {
+		t.Errorf("got instances %v, want none", ids(services))
+	}
+}
+
+func TestClone(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	defer r.Close()
+	in := newInstance("1", "helloworld")
+	_ = r.Register(ctx, in)
+	// 注册之后修改调用方的实例不影响注册表
+	in.Metadata["zone"] = "b"
+	in.Endpoints[0] = "changed"
+	services, _ := r.GetService(ctx, "helloworld")
+	if !reflect.DeepEqual(services[0], newInstance("1", "helloworld")) {
+		t.Errorf("got instance %+v", services[0])
+	}
+	// 返回的实例也是副本
+	services[0].Metadata["zone"] = "c"
+	services, _ = r.GetService(ctx, "helloworld")
+	if services[0].Metadata["zone"] != "a" {
+		t.Errorf("got zone %q, want %q", services[0].Metadata["zone"], "a")
+	}
+}
+
+func next(t *testing.T, w registry.Watcher, want ...string) {
+	t.Helper()
+	services, err := w.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(services); !slices.Equal(got, want) {
+		t.Errorf("got instances %v, want %v", got, want)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	defer r.Close()
+	w, err := r.Watch(ctx, "helloworld")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	_ = r.Register(ctx, newInstance("1", "helloworld"))
+	next(t, w, "1")
+	_ = r.Register(ctx, newInstance("2", "helloworld"))
+	next(t, w, "1", "2")
+	_ = r.Deregister(ctx, newInstance("1", "helloworld"))
+	next(t, w, "2")
+	_ = r.Deregister(ctx, newInstance("2", "helloworld"))
+	next(t, w)
+
+	// 没有被消费的旧快照被最新的快照替换
+	_ = r.Register(ctx, newInstance("1", "helloworld"))
+	_ = r.Register(ctx, newInstance("2", "helloworld"))
+	_ = r.Register(ctx, newInstance("3", "helloworld"))
+	next(t, w, "1", "2", "3")
+
+	// 其他服务的变化不会通知
+	_ = r.Register(ctx, newInstance("4", "other"))
+	select {
+	case s := <-w.(*watcher).event:
+		t.Errorf("got notification %v for another service", ids(s))
+	default:
+	}
+}
+
+func TestWatchExisting(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	defer r.Close()
+	_ = r.Register(ctx, newInstance("1", "helloworld"))
+	w, err := r.Watch(ctx, "helloworld")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+	// 第一次监听且实例列表不为空时立即返回
+	next(t, w, "1")
+
+	// 多个监听器都会收到通知
+	w2, _ := r.Watch(ctx, "helloworld")
+	defer w2.Stop()
+	next(t, w2, "1")
+	_ = r.Register(ctx, newInstance("2", "helloworld"))
+	next(t, w, "1", "2")
+	next(t, w2, "1", "2")
+}
+
+func TestWatchStop(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	defer r.Close()
+	w, _ := r.Watch(ctx, "helloworld")
+	if err := w.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Next(); !errors.Is(err, context.Canceled) {
+		t.Errorf("after Stop: got error %v, want %v", err, context.Canceled)
+	}
+	if len(r.watchers) != 0 {
+		t.Errorf("got %d watched services after Stop, want 0", len(r.watchers))
+	}
+
+	// 监听的 ctx 取消后 Next 返回
+	wctx, cancel := context.WithCancel(ctx)
+	w, _ = r.Watch(wctx, "helloworld")
+	defer w.Stop()
+	cancel()
+	if _, err := w.Next(); !errors.Is(err, context.Canceled) {
+		t.Errorf("after cancel: got error %v, want %v", err, context.Canceled)
+	}
+
+	// 注册表关闭后 Next 返回
+	w2, _ := r.Watch(ctx, "helloworld")
+	defer w2.Stop()
+	_ = r.Close()
+	if _, err := w2.Next(); !errors.Is(err, context.Canceled) {
+		t.Errorf("after Close: got error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	ctx := context.Background()
+	ttl := 100 * time.Millisecond
+	r := New(TTL(ttl))
+	defer r.Close()
+	w, _ := r.Watch(ctx, "helloworld")
+	defer w.Stop()
+	_ = r.Register(ctx, newInstance("1", "helloworld"))
+	next(t, w, "1")
+
+	// 超过 ttl 没有续约的实例被剔除并通知监听器
+	start := time.Now()
+	next(t, w)
+	if elapsed := time.Since(start); elapsed < ttl/2 {
+		t.Errorf("instance evicted after %v, want about %v", elapsed, ttl)
+	}
+	if services, _ := r.GetService(ctx, "helloworld"); len(services) != 0 {
+		t.Errorf("got instances %v after ttl, want none", ids(services))
+	}
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+
+	"kratos_c/registry"
+)
+
+var _ registry.Watcher = (*watcher)(nil)
+
+type watcher struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	r           *Registry
+	serviceName string
+	// 容量为1, 只保留最新的一份快照
+	event chan []*registry.ServiceInstance
+}
+
+func newWatcher(ctx context.Context, r *Registry, serviceName string) *watcher {
+	w := &watcher{
+		r:           r,
+		serviceName: serviceName,
+		event:       make(chan []*registry.ServiceInstance, 1),
+	}
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	return w
+}
+
+// notify 不会阻塞, 未被消费的旧快照会被新快照替换
+func (w *watcher) notify(s []*registry.ServiceInstance) {
+	for {
+		select {
+		case w.event <- s:
+			return
+		default:
+		}
+		select {
+		case <-w.event:
+		default:
+		}
+	}
+}
+
+func (w *watcher) Next() ([]*registry.ServiceInstance, error) {
+	select {
+	case <-w.ctx.Done():
+		return nil, w.ctx.Err()
+	case <-w.r.ctx.Done():
+		return nil, w.r.ctx.Err()
+	case s := <-w.event:
+		return s, nil
+	}
+}
+
+func (w *watcher) Stop() error {
+	w.cancel()
+	w.r.remove(w)
+	return nil
+}
@@ -14,3 +14,22 @@ type Registrar interface {
 	Register(ctx context.Context, service *ServiceInstance) error
 	Deregister(ctx context.Context, service *ServiceInstance) error
 }
+
+// Discovery is service discovery.
+type Discovery interface {
+	// GetService 根据服务名返回当前的实例列表
+	GetService(ctx context.Context, serviceName string) ([]*ServiceInstance, error)
+	// Watch 根据服务名创建一个监听器
+	Watch(ctx context.Context, serviceName string) (Watcher, error)
+}
+
+// Watcher is service watcher.
+type Watcher interface {
+	// Next 在以下两种情况返回完整的实例列表:
+	// 1. 第一次监听且实例列表不为空
+	// 2. 发现任何实例变化
+	// 否则一直阻塞, 直到 context 超时或者被取消
+	Next() ([]*ServiceInstance, error)
+	// Stop 关闭监听器
+	Stop() error
+}
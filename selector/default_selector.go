@@ -0,0 +1,115 @@
+package selector
+
+import (
+	"context"
+	"maps"
+	"sync/atomic"
+)
+
+var (
+	_ Selector = (*Default)(nil)
+	_ Builder  = (*DefaultBuilder)(nil)
+)
+
+// Default is composite selector.
+type Default struct {
+	NodeBuilder WeightedNodeBuilder
+	Balancer    Balancer
+
+	nodes atomic.Value
+}
+
+// Select is select one node.
+func (d *Default) Select(ctx context.Context, opts ...SelectOption) (selected Node, done DoneFunc, err error) {
+	var (
+		options    SelectOptions
+		candidates []WeightedNode
+	)
+	nodes, ok := d.nodes.Load().([]WeightedNode)
+	if !ok {
+		return nil, nil, ErrNoAvailable
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	if len(options.NodeFilters) > 0 {
+		newNodes := make([]Node, len(nodes))
+		for i, wc := range nodes {
+			newNodes[i] = wc
+		}
+		for _, filter := range options.NodeFilters {
+			newNodes = filter(ctx, newNodes)
+		}
+		candidates = make([]WeightedNode, 0, len(newNodes))
+		for _, n := range newNodes {
+			// filter 返回的节点不一定来自传入的列表, 忽略不是 WeightedNode 的节点
+			if wn, ok := n.(WeightedNode); ok {
+				candidates = append(candidates, wn)
+			}
+		}
+	} else {
+		candidates = nodes
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil, ErrNoAvailable
+	}
+	wn, done, err := d.Balancer.Pick(ctx, candidates)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, ok := FromPeerContext(ctx)
+	if ok {
+		p.Node = wn.Raw()
+	}
+	return wn.Raw(), done, nil
+}
+
+// Apply update nodes info.
+// 地址和属性都没有变化的节点会复用之前的 WeightedNode, 保留 p2c 等负载均衡器统计的延迟和成功率.
+// Balancer 实现了 Rebalancer 时也会收到新的节点列表, 用于清理已下线节点的状态.
+func (d *Default) Apply(nodes []Node) {
+	old, _ := d.nodes.Load().([]WeightedNode)
+	prev := make(map[string]WeightedNode, len(old))
+	for _, wn := range old {
+		prev[wn.Address()] = wn
+	}
+	weightedNodes := make([]WeightedNode, 0, len(nodes))
+	for _, n := range nodes {
+		if wn, ok := prev[n.Address()]; ok && sameNode(wn.Raw(), n) {
+			weightedNodes = append(weightedNodes, wn)
+			continue
+		}
+		weightedNodes = append(weightedNodes, d.NodeBuilder.Build(n))
+	}
+	d.nodes.Store(weightedNodes)
+	if r, ok := d.Balancer.(Rebalancer); ok {
+		r.Apply(nodes)
+	}
+}
+
+// sameNode 判断同一地址上的节点属性是否发生变化
+func sameNode(a, b Node) bool {
+	if a.Scheme() != b.Scheme() || a.ServiceName() != b.ServiceName() || a.Version() != b.Version() {
+		return false
+	}
+	aw, bw := a.InitialWeight(), b.InitialWeight()
+	if (aw == nil) != (bw == nil) || (aw != nil && *aw != *bw) {
+		return false
+	}
+	return maps.Equal(a.Metadata(), b.Metadata())
+}
+
+// DefaultBuilder is the builder of Default selector.
+type DefaultBuilder struct {
+	Node     WeightedNodeBuilder
+	Balancer BalancerBuilder
+}
+
+// Build create builder
+func (db *DefaultBuilder) Build() Selector {
+	return &Default{
+		NodeBuilder: db.Node,
+		Balancer:    db.Balancer.Build(),
+	}
+}
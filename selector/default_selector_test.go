@@ -0,0 +1,98 @@
+package selector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"kratos_c/registry"
+)
+
+type testWeightedNode struct {
+	Node
+}
+
+func (n *testWeightedNode) Raw() Node                  { return n.Node }
+func (n *testWeightedNode) Weight() float64            { return 1 }
+func (n *testWeightedNode) Pick() DoneFunc             { return func(context.Context, DoneInfo) {} }
+func (n *testWeightedNode) PickElapsed() time.Duration { return 0 }
+
+type testNodeBuilder struct{}
+
+func (testNodeBuilder) Build(n Node) WeightedNode { return &testWeightedNode{Node: n} }
+
+// testBalancer 总是选择第一个节点, 并记录 Apply 收到的节点
+type testBalancer struct {
+	applied []Node
+}
+
+func (b *testBalancer) Pick(_ context.Context, nodes []WeightedNode) (WeightedNode, DoneFunc, error) {
+	return nodes[0], nodes[0].Pick(), nil
+}
+
+func (b *testBalancer) Apply(nodes []Node) { b.applied = nodes }
+
+func newTestSelector() (*Default, *testBalancer) {
+	b := &testBalancer{}
+	return &Default{NodeBuilder: testNodeBuilder{}, Balancer: b}, b
+}
+
+func TestDefaultSelect(t *testing.T) {
+	s, b := newTestSelector()
+	if _, _, err := s.Select(context.Background()); !errors.Is(err, ErrNoAvailable) {
+		t.Fatalf("select before apply: got error %v, want %v", err, ErrNoAvailable)
+	}
+	a := NewNode("grpc", "127.0.0.1:9000", &registry.ServiceInstance{Name: "svc", Version: "v1"})
+	c := NewNode("grpc", "127.0.0.1:9001", &registry.ServiceInstance{Name: "svc", Version: "v2"})
+	s.Apply([]Node{a, c})
+	if len(b.applied) != 2 {
+		t.Errorf("balancer applied %d nodes, want 2", len(b.applied))
+	}
+
+	p := &Peer{}
+	n, done, err := s.Select(NewPeerContext(context.Background(), p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	done(context.Background(), DoneInfo{})
+	if n != a || p.Node != a {
+		t.Errorf("selected %v, peer %v, want %v", n, p.Node, a)
+	}
+
+	onlyV2 := WithNodeFilter(func(_ context.Context, nodes []Node) []Node {
+		var ret []Node
+		for _, n := range nodes {
+			if n.Version() == "v2" {
+				ret = append(ret, n)
+			}
+		}
+		return ret
+	})
+	if n, _, err = s.Select(context.Background(), onlyV2); err != nil || n != c {
+		t.Errorf("filtered select = %v, %v, want %v", n, err, c)
+	}
+
+	// filter 返回的不是 WeightedNode 时忽略, 而不是 panic
+	foreign := WithNodeFilter(func(context.Context, []Node) []Node { return []Node{a} })
+	if _, _, err = s.Select(context.Background(), foreign); !errors.Is(err, ErrNoAvailable) {
+		t.Errorf("foreign nodes: got error %v, want %v", err, ErrNoAvailable)
+	}
+}
+
+func TestDefaultApplyReusesNodes(t *testing.T) {
+	s, _ := newTestSelector()
+	ins := &registry.ServiceInstance{Name: "svc", Version: "v1", Metadata: map[string]string{"weight": "10"}}
+	s.Apply([]Node{NewNode("grpc", "127.0.0.1:9000", ins), NewNode("grpc", "127.0.0.1:9001", ins)})
+	before := s.nodes.Load().([]WeightedNode)
+
+	changed := &registry.ServiceInstance{Name: "svc", Version: "v1", Metadata: map[string]string{"weight": "20"}}
+	s.Apply([]Node{NewNode("grpc", "127.0.0.1:9000", ins), NewNode("grpc", "127.0.0.1:9001", changed)})
+	after := s.nodes.Load().([]WeightedNode)
+	if after[0] != before[0] {
+		t.Errorf("unchanged node should be reused")
+	}
+	if after[1] == before[1] {
+		t.Errorf("node with changed metadata should be rebuilt")
+	}
+}
@@ -0,0 +1,48 @@
+package filter
+
+import (
+	"context"
+	"math/rand"
+
+	"kratos_c/selector"
+	"kratos_c/transport"
+)
+
+// Percentage 将 percent% 的请求路由到 canary 选出的节点, 其余请求路由到剩下的节点.
+// 任一侧没有节点时退回到全部节点.
+func Percentage(percent int, canary selector.NodeFilter) selector.NodeFilter {
+	return func(ctx context.Context, nodes []selector.Node) []selector.Node {
+		return split(ctx, nodes, canary, rand.Intn(100) < percent)
+	}
+}
+
+// Header 将请求头 key 的值等于 value 的请求路由到 canary 选出的节点, 其余请求路由到剩下的节点.
+// 请求头取自 client 端的 transport.
+// 任一侧没有节点时退回到全部节点.
+func Header(key, value string, canary selector.NodeFilter) selector.NodeFilter {
+	return func(ctx context.Context, nodes []selector.Node) []selector.Node {
+		matched := false
+		if tr, ok := transport.FromClientContext(ctx); ok {
+			matched = tr.RequestHeader().Get(key) == value
+		}
+		return split(ctx, nodes, canary, matched)
+	}
+}
+
+func split(ctx context.Context, nodes []selector.Node, canary selector.NodeFilter, toCanary bool) []selector.Node {
+	picked := canary(ctx, nodes)
+	if toCanary {
+		if len(picked) == 0 {
+			return nodes
+		}
+		return picked
+	}
+	set := make(map[string]struct{}, len(picked))
+	for _, n := range picked {
+		set[n.Address()] = struct{}{}
+	}
+	return keep(nodes, func(n selector.Node) bool {
+		_, ok := set[n.Address()]
+		return !ok
+	}, options{fallback: true})
+}
@@ -0,0 +1,37 @@
+package filter
+
+import "kratos_c/selector"
+
+// Option is filter option.
+type Option func(o *options)
+
+type options struct {
+	fallback bool
+}
+
+// Fallback 过滤后没有可用节点时返回原节点列表, 而不是空列表.
+func Fallback() Option {
+	return func(o *options) { o.fallback = true }
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// keep 保留满足 fn 的节点
+func keep(nodes []selector.Node, fn func(selector.Node) bool, o options) []selector.Node {
+	newNodes := make([]selector.Node, 0, len(nodes))
+	for _, n := range nodes {
+		if fn(n) {
+			newNodes = append(newNodes, n)
+		}
+	}
+	if len(newNodes) == 0 && o.fallback {
+		return nodes
+	}
+	return newNodes
+}
@@ -0,0 +1,29 @@
+package filter
+
+import (
+	"context"
+
+	"kratos_c/selector"
+)
+
+// Metadata 只保留元数据包含全部 md 键值对的节点.
+func Metadata(md map[string]string, opts ...Option) selector.NodeFilter {
+	return MetadataFunc(func(nmd map[string]string) bool {
+		for k, v := range md {
+			if nv, ok := nmd[k]; !ok || nv != v {
+				return false
+			}
+		}
+		return true
+	}, opts...)
+}
+
+// MetadataFunc 只保留元数据满足 fn 的节点.
+func MetadataFunc(fn func(md map[string]string) bool, opts ...Option) selector.NodeFilter {
+	o := newOptions(opts)
+	return func(_ context.Context, nodes []selector.Node) []selector.Node {
+		return keep(nodes, func(n selector.Node) bool {
+			return fn(n.Metadata())
+		}, o)
+	}
+}
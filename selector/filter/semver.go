@@ -0,0 +1,295 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// version is a parsed semantic version, 支持省略 minor 和 patch, 以及 v 前缀.
+type version struct {
+	major, minor, patch uint64
+	pre                 []string
+}
+
+func parseVersion(s string) (version, error) {
+	var v version
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		if i == len(s)-1 {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		v.pre = strings.Split(s[i+1:], ".")
+		s = s[:i]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	nums := [3]*uint64{&v.major, &v.minor, &v.patch}
+	for i, p := range parts {
+		n, err := strconv.ParseUint(p, 10, 64)
+		if err != nil {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		*nums[i] = n
+	}
+	return v, nil
+}
+
+// compare returns -1, 0 or 1.
+func (v version) compare(o version) int {
+	if c := compareUint(v.major, o.major); c != 0 {
+		return c
+	}
+	if c := compareUint(v.minor, o.minor); c != 0 {
+		return c
+	}
+	if c := compareUint(v.patch, o.patch); c != 0 {
+		return c
+	}
+	// 有预发布标识的版本低于正式版本
+	switch {
+	case len(v.pre) == 0 && len(o.pre) == 0:
+		return 0
+	case len(v.pre) == 0:
+		return 1
+	case len(o.pre) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.pre) && i < len(o.pre); i++ {
+		if c := comparePre(v.pre[i], o.pre[i]); c != 0 {
+			return c
+		}
+	}
+	return compareUint(uint64(len(v.pre)), uint64(len(o.pre)))
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// comparePre 数字标识按数值比较且低于非数字标识, 非数字标识按字典序比较
+func comparePre(a, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareUint(an, bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+type comparator struct {
+	op string
+	v  version
+	// hi 只用于不完整版本的 !=, 例如 !=1.2 表示不在 [1.2.0, 1.3.0) 内
+	hi *version
+}
+
+func (c comparator) check(v version) bool {
+	r := v.compare(c.v)
+	switch c.op {
+	case "", "=":
+		return r == 0
+	case "!=":
+		if c.hi != nil {
+			return r < 0 || v.compare(*c.hi) >= 0
+		}
+		return r != 0
+	case ">":
+		return r > 0
+	case ">=":
+		return r >= 0
+	case "<":
+		return r < 0
+	case "<=":
+		return r <= 0
+	}
+	return false
+}
+
+// constraint 是以 || 分隔的若干组比较条件, 满足任意一组即可, 组内条件需全部满足.
+// 与 npm 一致, 预发布版本只有在组内某个条件带有相同 major.minor.patch 的预发布标识时才可能匹配.
+type constraint [][]comparator
+
+func (c constraint) check(v version) bool {
+	for _, group := range c {
+		ok := len(v.pre) == 0
+		for _, cmp := range group {
+			if len(cmp.v.pre) > 0 && cmp.v.major == v.major && cmp.v.minor == v.minor && cmp.v.patch == v.patch {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		for _, cmp := range group {
+			if !cmp.check(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+var operators = []string{">=", "<=", "!=", ">", "<", "=", "~", "^"}
+
+func parseConstraint(s string) (constraint, error) {
+	var c constraint
+	for _, group := range strings.Split(s, "||") {
+		var cmps []comparator
+		for _, f := range fields(group) {
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(f, o) {
+					op = o
+					break
+				}
+			}
+			v, n, err := parsePartial(strings.TrimPrefix(f, op))
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %w", s, err)
+			}
+			exp, err := expand(op, v, n)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %w", s, err)
+			}
+			cmps = append(cmps, exp...)
+		}
+		if len(cmps) == 0 {
+			return nil, fmt.Errorf("invalid constraint %q", s)
+		}
+		c = append(c, cmps)
+	}
+	return c, nil
+}
+
+// fields 按空格和逗号切分一组条件, 运算符和版本之间允许有空格, 例如 ">= 1.2.0" 和 ">=1.2.0" 等价.
+func fields(group string) []string {
+	var ret []string
+	pending := ""
+	for _, f := range strings.FieldsFunc(group, func(r rune) bool { return r == ' ' || r == '\t' || r == ',' }) {
+		if slices.Contains(operators, f) {
+			pending += f
+			continue
+		}
+		ret = append(ret, pending+f)
+		pending = ""
+	}
+	if pending != "" {
+		// 只有运算符没有版本, 交给 parsePartial 报错
+		ret = append(ret, pending)
+	}
+	return ret
+}
+
+// parsePartial 解析约束中的版本, 返回给出的版本段数 n, 缺省的段和 x, X, * 一样视为通配.
+// 例如 1.2 和 1.2.x 的 n 都为 2, * 的 n 为 0.
+func parsePartial(s string) (version, int, error) {
+	core, suffix := strings.TrimPrefix(s, "v"), ""
+	if i := strings.IndexAny(core, "-+"); i >= 0 {
+		core, suffix = core[:i], core[i:]
+	}
+	parts := strings.Split(core, ".")
+	n := 0
+	for n < len(parts) && n < 3 && !isWildcard(parts[n]) {
+		n++
+	}
+	for _, p := range parts[n:] {
+		if !isWildcard(p) {
+			return version{}, 0, fmt.Errorf("invalid version %q", s)
+		}
+	}
+	if n == 0 && suffix == "" {
+		return version{}, 0, nil
+	}
+	v, err := parseVersion(strings.Join(parts[:n], ".") + suffix)
+	if err != nil {
+		return v, 0, err
+	}
+	if len(v.pre) > 0 && n < 3 {
+		return v, 0, fmt.Errorf("invalid version %q", s)
+	}
+	return v, n, nil
+}
+
+func isWildcard(s string) bool {
+	return s == "x" || s == "X" || s == "*"
+}
+
+// next 返回不完整版本对应区间的上界, 例如 1.2 => 1.3.0, 1 => 2.0.0
+func next(v version, n int) version {
+	if n == 1 {
+		return version{major: v.major + 1}
+	}
+	return version{major: v.major, minor: v.minor + 1}
+}
+
+// expand 将 ~, ^ 和不完整版本(X-range)展开为上下界, n 为约束中给出的版本段数.
+// 与 npm 一致: 1.2 => >=1.2.0 <1.3.0, >1.2 => >=1.3.0, <=1.2 => <1.3.0, * => >=0.0.0.
+func expand(op string, v version, n int) ([]comparator, error) {
+	if n == 0 {
+		switch op {
+		case "", "=", ">=", "<=", "~", "^":
+			return []comparator{{op: ">=", v: version{}}}, nil
+		}
+		return nil, fmt.Errorf("operator %q can not be used with *", op)
+	}
+	switch op {
+	case "~":
+		// ~1.2.3 => >=1.2.3 <1.3.0, ~1 => >=1.0.0 <2.0.0
+		upper := version{major: v.major, minor: v.minor + 1}
+		if n == 1 {
+			upper = version{major: v.major + 1}
+		}
+		return []comparator{{op: ">=", v: v}, {op: "<", v: upper}}, nil
+	case "^":
+		// ^1.2.3 => >=1.2.3 <2.0.0, ^0.2.3 => >=0.2.3 <0.3.0, ^0.0.3 => >=0.0.3 <0.0.4
+		var upper version
+		switch {
+		case v.major > 0 || n == 1:
+			upper = version{major: v.major + 1}
+		case v.minor > 0 || n == 2:
+			upper = version{minor: v.minor + 1}
+		default:
+			upper = version{patch: v.patch + 1}
+		}
+		return []comparator{{op: ">=", v: v}, {op: "<", v: upper}}, nil
+	}
+	if n == 3 {
+		return []comparator{{op: op, v: v}}, nil
+	}
+	upper := next(v, n)
+	switch op {
+	case "", "=":
+		return []comparator{{op: ">=", v: v}, {op: "<", v: upper}}, nil
+	case "!=":
+		return []comparator{{op: "!=", v: v, hi: &upper}}, nil
+	case ">":
+		return []comparator{{op: ">=", v: upper}}, nil
+	case "<=":
+		return []comparator{{op: "<", v: upper}}, nil
+	}
+	// >=1.2 => >=1.2.0, <1.2 => <1.2.0
+	return []comparator{{op: op, v: v}}, nil
+}
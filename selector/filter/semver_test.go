@@ -0,0 +1,131 @@
+package filter
+
+import (
+	"testing"
+)
+
+func TestVersionCompare(t *testing.T) {
+	// semver.org 第 11 条给出的顺序
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			a, err := parseVersion(ordered[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := parseVersion(ordered[j])
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := compareUint(uint64(i), uint64(j))
+			if got := a.compare(b); got != want {
+				t.Errorf("compare(%s, %s) = %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		err  bool
+	}{
+		{in: "1.2.3", want: "1.2.3"},
+		{in: "v1.2.3", want: "1.2.3"},
+		{in: " 1.2.3 ", want: "1.2.3"},
+		{in: "1.2", want: "1.2.0"},
+		{in: "1", want: "1.0.0"},
+		{in: "1.2.3+build.5", want: "1.2.3"},
+		{in: "1.2.3-rc.1+build", want: "1.2.3-rc.1"},
+		{in: "1.2.3.4", err: true},
+		{in: "1.2.a", err: true},
+		{in: "1.2.3-", err: true},
+		{in: "", err: true},
+	}
+	for _, tt := range tests {
+		v, err := parseVersion(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("parseVersion(%q) error = %v, want error %v", tt.in, err, tt.err)
+			continue
+		}
+		if tt.err {
+			continue
+		}
+		if want, _ := parseVersion(tt.want); v.compare(want) != 0 {
+			t.Errorf("parseVersion(%q) = %v, want %s", tt.in, v, tt.want)
+		}
+	}
+}
+
+func TestConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		match      []string
+		miss       []string
+	}{
+		{"1.2.3", []string{"1.2.3", "v1.2.3"}, []string{"1.2.4", "1.2.3-rc.1"}},
+		{"=1.2.3", []string{"1.2.3"}, []string{"1.2.2"}},
+		{"!=1.2.3", []string{"1.2.2", "1.2.4"}, []string{"1.2.3"}},
+		{">1.2.3", []string{"1.2.4", "2.0.0"}, []string{"1.2.3", "1.0.0", "1.3.0-beta"}},
+		{">=1.2.3", []string{"1.2.3", "1.3.0"}, []string{"1.2.2"}},
+		{"<1.2.3", []string{"1.2.2", "0.9.0"}, []string{"1.2.3", "1.2.3-rc.1"}},
+		{"<=1.2.3", []string{"1.2.3", "1.0.0"}, []string{"1.2.4"}},
+		{"^1.2.3", []string{"1.2.3", "1.9.0"}, []string{"1.2.2", "2.0.0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0", "0.2.2"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"^1", []string{"1.0.0", "1.9.9"}, []string{"2.0.0", "0.9.0"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0", "1.2.2"}},
+		{"~1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		// X-range
+		{"1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0", "1.1.9"}},
+		{"1.2.x", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"1.X", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{"*", []string{"0.0.0", "9.9.9"}, []string{"1.0.0-rc.1"}},
+		{">1.2", []string{"1.3.0"}, []string{"1.2.9"}},
+		{"<=1.2", []string{"1.2.9"}, []string{"1.3.0"}},
+		{"!=1.2", []string{"1.1.9", "1.3.0"}, []string{"1.2.0", "1.2.5"}},
+		// 空格分隔的条件需要全部满足
+		{">=1.2.0 <2.0.0", []string{"1.2.0", "1.9.9"}, []string{"2.0.0", "1.1.0"}},
+		{">=1.2.0, <2.0.0", []string{"1.5.0"}, []string{"2.0.0"}},
+		// || 满足任意一组即可
+		{"~1.2.3 || 2.0.0", []string{"1.2.5", "2.0.0"}, []string{"1.3.0", "2.0.1"}},
+		{"<1.0.0 || >=2.0.0", []string{"0.5.0", "2.1.0"}, []string{"1.5.0"}},
+		// 运算符和版本之间允许空白
+		{">= 1.2.0", []string{"1.2.0"}, []string{"1.1.0"}},
+		{"  >=  1.2.0 \t<  2.0.0  ", []string{"1.5.0"}, []string{"2.0.0"}},
+		{"^ 1.2 || = 3.0.0", []string{"1.5.0", "3.0.0"}, []string{"2.0.0"}},
+		// 预发布版本只有在条件中有相同 major.minor.patch 的预发布标识时才匹配
+		{">=1.2.3-beta.2", []string{"1.2.3-beta.2", "1.2.3-beta.11", "1.2.3-rc.1", "1.2.3", "1.3.0"}, []string{"1.2.3-beta.1", "1.3.0-rc.1"}},
+		{">1.2.3-alpha <1.2.3", []string{"1.2.3-alpha.1", "1.2.3-beta"}, []string{"1.2.3-alpha", "1.2.3"}},
+	}
+	for _, tt := range tests {
+		c, err := parseConstraint(tt.constraint)
+		if err != nil {
+			t.Errorf("parseConstraint(%q): %v", tt.constraint, err)
+			continue
+		}
+		for _, s := range tt.match {
+			if v, _ := parseVersion(s); !c.check(v) {
+				t.Errorf("%q should match %s", tt.constraint, s)
+			}
+		}
+		for _, s := range tt.miss {
+			if v, _ := parseVersion(s); c.check(v) {
+				t.Errorf("%q should not match %s", tt.constraint, s)
+			}
+		}
+	}
+}
+
+func TestConstraintInvalid(t *testing.T) {
+	for _, s := range []string{"", "||", ">=", "1.2.3 >=", ">=a.b", "1.2.3.4", ">*", "1.x.3", "1.2-rc.1", ">= >= 1.0.0"} {
+		if _, err := parseConstraint(s); err == nil {
+			t.Errorf("parseConstraint(%q) should fail", s)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package filter
+
+import (
+	"context"
+
+	"kratos_c/selector"
+)
+
+// Version 只保留版本号等于 version 的节点.
+func Version(version string, opts ...Option) selector.NodeFilter {
+	o := newOptions(opts)
+	return func(_ context.Context, nodes []selector.Node) []selector.Node {
+		return keep(nodes, func(n selector.Node) bool {
+			return n.Version() == version
+		}, o)
+	}
+}
+
+// VersionRange 只保留版本号满足 semver 约束的节点, 例如 ">=1.2.0 <2.0.0", "^1.3", "~1.2.3 || 2.0.0", "1.2.x".
+// 与 npm 一致, 不完整的版本按 X-range 处理, 例如 "1.2" 等价于 ">=1.2.0 <1.3.0".
+// 版本号无法解析的节点会被过滤掉.
+func VersionRange(constraint string, opts ...Option) (selector.NodeFilter, error) {
+	c, err := parseConstraint(constraint)
+	if err != nil {
+		return nil, err
+	}
+	o := newOptions(opts)
+	return func(_ context.Context, nodes []selector.Node) []selector.Node {
+		return keep(nodes, func(n selector.Node) bool {
+			v, err := parseVersion(n.Version())
+			if err != nil {
+				return false
+			}
+			return c.check(v)
+		}, o)
+	}, nil
+}
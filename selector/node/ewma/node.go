@@ -0,0 +1,176 @@
+package ewma
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"kratos_c/errors"
+	"kratos_c/selector"
+)
+
+const (
+	// The mean lifetime of `cost`, it reaches its half-life after Tau*ln(2).
+	tau = int64(time.Millisecond * 600)
+	// if statistic not collected,we add a big lag penalty to endpoint
+	penalty = uint64(time.Microsecond * 100)
+)
+
+var (
+	_ selector.WeightedNode        = (*Node)(nil)
+	_ selector.WeightedNodeBuilder = (*Builder)(nil)
+)
+
+// Node is endpoint instance
+type Node struct {
+	selector.Node
+
+	// client statistic data
+	lag       int64
+	success   uint64
+	inflight  int64
+	inflights [200]int64
+	// last collected timestamp
+	stamp int64
+	// request number in a period time
+	reqs int64
+	// last lastPick timestamp
+	lastPick int64
+
+	errHandler func(err error) (isErr bool)
+	cachedTime int64
+	lock       sync.RWMutex
+}
+
+// Builder is ewma node builder.
+type Builder struct {
+	// ErrHandler 判断调用错误是否计为失败, 为空时 5xx 错误 (包括超时、连接错误和 gRPC Unavailable 等) 计为失败
+	ErrHandler func(err error) (isErr bool)
+}
+
+// Build create a weighted node.
+func (b *Builder) Build(n selector.Node) selector.WeightedNode {
+	s := &Node{
+		Node:       n,
+		lag:        0,
+		success:    1000,
+		inflight:   1,
+		errHandler: b.ErrHandler,
+	}
+	return s
+}
+
+func (n *Node) health() uint64 {
+	return atomic.LoadUint64(&n.success)
+}
+
+func (n *Node) load() (load uint64) {
+	now := time.Now().UnixNano()
+	avgLag := atomic.LoadInt64(&n.lag)
+	lastPredictTs := atomic.LoadInt64(&n.cachedTime)
+	predicted := n.predictInflight(now, lastPredictTs)
+	inflight := atomic.LoadInt64(&n.inflight)
+	if predicted > inflight {
+		inflight = predicted
+	}
+	if avgLag == 0 {
+		// penalty is the penalty value when there is no data when the node is just started.
+		load = penalty * uint64(inflight)
+		return
+	}
+	return uint64(avgLag) * uint64(inflight)
+}
+
+// predictInflight 根据最近一段时间内的请求延迟估算当前的并发请求数
+func (n *Node) predictInflight(now, lastPredictTs int64) int64 {
+	if now-lastPredictTs <= int64(time.Millisecond*5) {
+		return 0
+	}
+	if !atomic.CompareAndSwapInt64(&n.cachedTime, lastPredictTs, now) {
+		return 0
+	}
+	var total int64
+	n.lock.RLock()
+	for _, v := range n.inflights {
+		if v != 0 && now-v > atomic.LoadInt64(&n.lag) {
+			total++
+		}
+	}
+	n.lock.RUnlock()
+	return total
+}
+
+// Pick pick a node.
+func (n *Node) Pick() selector.DoneFunc {
+	start := time.Now().UnixNano()
+	atomic.StoreInt64(&n.lastPick, start)
+	atomic.AddInt64(&n.inflight, 1)
+	reqs := atomic.AddInt64(&n.reqs, 1)
+	slot := reqs % int64(len(n.inflights))
+	n.lock.Lock()
+	n.inflights[slot] = start
+	n.lock.Unlock()
+	return func(_ context.Context, di selector.DoneInfo) {
+		n.lock.Lock()
+		n.inflights[slot] = 0
+		n.lock.Unlock()
+		atomic.AddInt64(&n.inflight, -1)
+
+		now := time.Now().UnixNano()
+		// get moving average ratio w
+		stamp := atomic.SwapInt64(&n.stamp, now)
+		td := now - stamp
+		if td < 0 {
+			td = 0
+		}
+		w := math.Exp(float64(-td) / float64(tau))
+
+		lag := now - start
+		if lag < 0 {
+			lag = 0
+		}
+		oldLag := atomic.LoadInt64(&n.lag)
+		if oldLag == 0 {
+			w = 0.0
+		}
+		lag = int64(float64(oldLag)*w + float64(lag)*(1.0-w))
+		atomic.StoreInt64(&n.lag, lag)
+
+		success := uint64(1000) // error value ,if error set 1
+		if di.Err != nil && n.isFailure(di.Err) {
+			success = 0
+		}
+		oldSuc := atomic.LoadUint64(&n.success)
+		success = uint64(float64(oldSuc)*w + float64(success)*(1.0-w))
+		atomic.StoreUint64(&n.success, success)
+	}
+}
+
+func (n *Node) isFailure(err error) bool {
+	if n.errHandler != nil {
+		return n.errHandler(err)
+	}
+	// 调用方主动取消不代表节点异常
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	return errors.FromError(err).Code >= 500
+}
+
+// Weight is node effective weight.
+func (n *Node) Weight() (weight float64) {
+	weight = float64(n.health()*uint64(time.Second)) / float64(n.load())
+	return
+}
+
+// PickElapsed is time elapsed since the latest pick
+func (n *Node) PickElapsed() time.Duration {
+	return time.Duration(time.Now().UnixNano() - atomic.LoadInt64(&n.lastPick))
+}
+
+// Raw returns the original node
+func (n *Node) Raw() selector.Node {
+	return n.Node
+}
@@ -0,0 +1,69 @@
+package ewma
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"kratos_c/errors"
+	"kratos_c/selector"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsFailure(t *testing.T) {
+	n := (&Builder{}).Build(selector.NewNode("grpc", "127.0.0.1:9000", nil)).(*Node)
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"canceled", context.Canceled, false},
+		{"wrapped canceled", fmt.Errorf("call: %w", context.Canceled), false},
+		{"grpc canceled", status.Error(codes.Canceled, "canceled"), false},
+		{"deadline", context.DeadlineExceeded, true},
+		{"net error", &net.OpError{Op: "dial", Err: fmt.Errorf("connection refused")}, true},
+		{"grpc unavailable", status.Error(codes.Unavailable, "unavailable"), true},
+		{"grpc not found", status.Error(codes.NotFound, "not found"), false},
+		{"http 503", errors.ServiceUnavailable("DOWN", "down"), true},
+		{"http 400", errors.BadRequest("INVALID", "invalid"), false},
+	}
+	for _, tt := range tests {
+		if got := n.isFailure(tt.err); got != tt.want {
+			t.Errorf("%s: isFailure() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestErrHandler(t *testing.T) {
+	b := &Builder{ErrHandler: func(err error) bool { return errors.IsBadRequest(err) }}
+	n := b.Build(selector.NewNode("grpc", "127.0.0.1:9000", nil)).(*Node)
+	if !n.isFailure(errors.BadRequest("INVALID", "invalid")) || n.isFailure(errors.ServiceUnavailable("DOWN", "down")) {
+		t.Errorf("ErrHandler should replace the default classification")
+	}
+}
+
+func TestWeight(t *testing.T) {
+	newNode := func() selector.WeightedNode {
+		return (&Builder{}).Build(selector.NewNode("grpc", "127.0.0.1:9000", nil))
+	}
+	ok, failed, canceled := newNode(), newNode(), newNode()
+	for range 10 {
+		ok.Pick()(context.Background(), selector.DoneInfo{})
+		failed.Pick()(context.Background(), selector.DoneInfo{Err: errors.ServiceUnavailable("DOWN", "down")})
+		canceled.Pick()(context.Background(), selector.DoneInfo{Err: context.Canceled})
+		time.Sleep(time.Millisecond)
+	}
+	if failed.Weight() >= ok.Weight() {
+		t.Errorf("failed node weight %v should be lower than %v", failed.Weight(), ok.Weight())
+	}
+	if h := canceled.(*Node).health(); h != 1000 {
+		t.Errorf("canceled requests should not reduce health, got %d", h)
+	}
+	if ok.PickElapsed() > time.Second {
+		t.Errorf("PickElapsed = %v", ok.PickElapsed())
+	}
+}
@@ -0,0 +1,16 @@
+package selector
+
+// SelectOptions is Select Options.
+type SelectOptions struct {
+	NodeFilters []NodeFilter
+}
+
+// SelectOption is Selector option.
+type SelectOption func(*SelectOptions)
+
+// WithNodeFilter with select filters
+func WithNodeFilter(fn ...NodeFilter) SelectOption {
+	return func(opts *SelectOptions) {
+		opts.NodeFilters = fn
+	}
+}
@@ -0,0 +1,81 @@
+package p2c
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kratos_c/selector"
+)
+
+type testNode struct {
+	selector.Node
+	weight  float64
+	elapsed time.Duration
+	picked  int
+}
+
+func (n *testNode) Raw() selector.Node { return n.Node }
+func (n *testNode) Weight() float64    { return n.weight }
+func (n *testNode) Pick() selector.DoneFunc {
+	n.picked++
+	return func(context.Context, selector.DoneInfo) {}
+}
+func (n *testNode) PickElapsed() time.Duration { return n.elapsed }
+
+func newTestNode(addr string, weight float64) *testNode {
+	return &testNode{Node: selector.NewNode("grpc", addr, nil), weight: weight}
+}
+
+func TestPick(t *testing.T) {
+	b := (&Builder{}).Build()
+	if _, _, err := b.Pick(context.Background(), nil); err != selector.ErrNoAvailable {
+		t.Errorf("got error %v, want %v", err, selector.ErrNoAvailable)
+	}
+
+	only := newTestNode("a", 1)
+	if n, _, err := b.Pick(context.Background(), []selector.WeightedNode{only}); err != nil || n != only {
+		t.Errorf("single node: got %v, %v", n, err)
+	}
+
+	// 两个节点时总是选择权重更高的那个
+	heavy, light := newTestNode("a", 10), newTestNode("b", 1)
+	for range 100 {
+		n, _, err := b.Pick(context.Background(), []selector.WeightedNode{light, heavy})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != heavy {
+			t.Fatalf("picked %s, want the heavier node", n.Address())
+		}
+	}
+}
+
+func TestForcePick(t *testing.T) {
+	b := (&Builder{}).Build()
+	heavy, light := newTestNode("a", 10), newTestNode("b", 1)
+	// 权重低的节点超过 forcePick 没有被选中过, 强制选中一次以更新它的统计
+	light.elapsed = forcePick + time.Second
+	n, _, err := b.Pick(context.Background(), []selector.WeightedNode{heavy, light})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != light {
+		t.Errorf("picked %s, want the node that was not picked for a long time", n.Address())
+	}
+}
+
+func TestSpread(t *testing.T) {
+	b := (&Builder{}).Build()
+	nodes := []selector.WeightedNode{newTestNode("a", 1), newTestNode("b", 1), newTestNode("c", 1)}
+	for range 300 {
+		if _, _, err := b.Pick(context.Background(), nodes); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, n := range nodes {
+		if n.(*testNode).picked == 0 {
+			t.Errorf("node %s was never picked", n.Address())
+		}
+	}
+}
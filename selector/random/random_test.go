@@ -0,0 +1,31 @@
+package random
+
+import (
+	"context"
+	"testing"
+
+	"kratos_c/selector"
+)
+
+func TestRandom(t *testing.T) {
+	s := New()
+	if _, _, err := s.Select(context.Background()); err != selector.ErrNoAvailable {
+		t.Errorf("got error %v, want %v", err, selector.ErrNoAvailable)
+	}
+	s.Apply([]selector.Node{
+		selector.NewNode("http", "a", nil),
+		selector.NewNode("http", "b", nil),
+	})
+	picked := make(map[string]int)
+	for range 200 {
+		n, done, err := s.Select(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		done(context.Background(), selector.DoneInfo{})
+		picked[n.Address()]++
+	}
+	if len(picked) != 2 {
+		t.Errorf("picked = %v, want both nodes", picked)
+	}
+}
@@ -1,5 +1,33 @@
 package selector
 
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoAvailable is no available node.
+var ErrNoAvailable = errors.New("no available node")
+
+// Selector is node pick balancer.
+type Selector interface {
+	Rebalancer
+
+	// Select nodes
+	// if err == nil, selected and done must not be empty.
+	Select(ctx context.Context, opts ...SelectOption) (selected Node, done DoneFunc, err error)
+}
+
+// Rebalancer is nodes rebalancer.
+type Rebalancer interface {
+	// Apply is apply all nodes when any changes happen
+	Apply(nodes []Node)
+}
+
+// Builder build selector
+type Builder interface {
+	Build() Selector
+}
+
 type Node interface {
 	Scheme() string
 	Address() string
@@ -8,3 +36,24 @@ type Node interface {
 	Version() string
 	Metadata() map[string]string
 }
+
+// DoneInfo is callback info when RPC invoke done.
+type DoneInfo struct {
+	// Response Error
+	Err error
+	// Response Metadata
+	ReplyMD ReplyMD
+
+	// BytesSent indicates if any bytes have been sent to the server.
+	BytesSent bool
+	// BytesReceived indicates if any byte has been received from the server.
+	BytesReceived bool
+}
+
+// ReplyMD is Reply Metadata.
+type ReplyMD interface {
+	Get(key string) string
+}
+
+// DoneFunc is callback function when RPC invoke done.
+type DoneFunc func(ctx context.Context, di DoneInfo)
@@ -0,0 +1,101 @@
+package wrr
+
+import (
+	"context"
+	"sync"
+
+	"kratos_c/selector"
+	"kratos_c/selector/node/direct"
+)
+
+const (
+	// Name is wrr(Weighted Round Robin) balancer name
+	Name = "wrr"
+)
+
+var (
+	_ selector.Balancer   = (*Balancer)(nil)
+	_ selector.Rebalancer = (*Balancer)(nil)
+)
+
+// Option is wrr builder option.
+type Option func(o *options)
+
+// options is wrr builder options
+type options struct{}
+
+// Balancer is a wrr balancer.
+type Balancer struct {
+	mu            sync.Mutex
+	currentWeight map[string]float64
+}
+
+// New creates a wrr selector.
+func New(opts ...Option) selector.Selector {
+	return NewBuilder(opts...).Build()
+}
+
+// Pick is pick a weighted node, 使用平滑加权轮询算法.
+func (p *Balancer) Pick(_ context.Context, nodes []selector.WeightedNode) (selector.WeightedNode, selector.DoneFunc, error) {
+	if len(nodes) == 0 {
+		return nil, nil, selector.ErrNoAvailable
+	}
+	var totalWeight float64
+	var selected selector.WeightedNode
+	var selectWeight float64
+
+	// nginx 的平滑加权轮询: 每轮所有节点 current += weight, 选出 current 最大者, 再令其 current -= total
+	p.mu.Lock()
+	for _, node := range nodes {
+		totalWeight += node.Weight()
+		cwt := p.currentWeight[node.Address()]
+		// current += effectiveWeight
+		cwt += node.Weight()
+		p.currentWeight[node.Address()] = cwt
+		if selected == nil || selectWeight < cwt {
+			selectWeight = cwt
+			selected = node
+		}
+	}
+	p.currentWeight[selected.Address()] = selectWeight - totalWeight
+	p.mu.Unlock()
+
+	d := selected.Pick()
+	return selected, d, nil
+}
+
+// Apply 在节点列表变化时清理已经下线的节点, 避免 currentWeight 随节点变更无限增长.
+// 不能在 Pick 中清理: filter 每次只传入部分节点, 会清掉其他节点的轮询状态.
+func (p *Balancer) Apply(nodes []selector.Node) {
+	alive := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		alive[node.Address()] = struct{}{}
+	}
+	p.mu.Lock()
+	for addr := range p.currentWeight {
+		if _, ok := alive[addr]; !ok {
+			delete(p.currentWeight, addr)
+		}
+	}
+	p.mu.Unlock()
+}
+
+// NewBuilder returns a selector builder with wrr balancer
+func NewBuilder(opts ...Option) selector.Builder {
+	var option options
+	for _, opt := range opts {
+		opt(&option)
+	}
+	return &selector.DefaultBuilder{
+		Balancer: &Builder{},
+		Node:     &direct.Builder{},
+	}
+}
+
+// Builder is wrr builder
+type Builder struct{}
+
+// Build creates Balancer
+func (b *Builder) Build() selector.Balancer {
+	return &Balancer{currentWeight: make(map[string]float64)}
+}
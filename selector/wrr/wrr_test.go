@@ -0,0 +1,83 @@
+package wrr
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"kratos_c/registry"
+	"kratos_c/selector"
+	"kratos_c/selector/node/direct"
+)
+
+func node(addr, weight string) selector.Node {
+	return selector.NewNode("http", addr, &registry.ServiceInstance{Metadata: map[string]string{"weight": weight}})
+}
+
+func TestWrr(t *testing.T) {
+	s := New()
+	s.Apply([]selector.Node{node("a", "3"), node("b", "2"), node("c", "1")})
+	var got []string
+	for range 6 {
+		n, done, err := s.Select(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		done(context.Background(), selector.DoneInfo{})
+		got = append(got, n.Address())
+	}
+	if want := []string{"a", "b", "a", "c", "b", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("picks = %v, want %v", got, want)
+	}
+}
+
+// TestWrrWithFilter 交替使用 filter, 被过滤掉的节点不应丢失轮询状态
+func TestWrrWithFilter(t *testing.T) {
+	s := New()
+	s.Apply([]selector.Node{node("a", "3"), node("b", "2"), node("c", "1")})
+	withoutA := selector.WithNodeFilter(func(_ context.Context, nodes []selector.Node) []selector.Node {
+		var ret []selector.Node
+		for _, n := range nodes {
+			if n.Address() != "a" {
+				ret = append(ret, n)
+			}
+		}
+		return ret
+	})
+	var got []string
+	for i := range 12 {
+		var opts []selector.SelectOption
+		if i%2 == 1 {
+			opts = append(opts, withoutA)
+		}
+		n, _, err := s.Select(context.Background(), opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, n.Address())
+	}
+	if want := []string{"a", "b", "b", "c", "a", "b", "c", "b", "a", "b", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("picks = %v, want %v", got, want)
+	}
+}
+
+func TestApplyPrunesRemovedNodes(t *testing.T) {
+	b := (&Builder{}).Build().(*Balancer)
+	s := &selector.Default{NodeBuilder: &direct.Builder{}, Balancer: b}
+	s.Apply([]selector.Node{node("a", "1"), node("b", "1"), node("c", "1")})
+	for range 3 {
+		if _, _, err := s.Select(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Apply([]selector.Node{node("a", "1"), node("c", "1")})
+	if _, ok := b.currentWeight["b"]; ok || len(b.currentWeight) != 2 {
+		t.Errorf("currentWeight = %v, want only a and c", b.currentWeight)
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	if _, _, err := New().Select(context.Background()); err != selector.ErrNoAvailable {
+		t.Errorf("got error %v, want %v", err, selector.ErrNoAvailable)
+	}
+}
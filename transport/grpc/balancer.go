@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"kratos_c/registry"
+	"kratos_c/selector"
+	"kratos_c/transport"
+
+	gBalancer "google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	balancerName = "selector"
+)
+
+var (
+	_ base.PickerBuilder = (*balancerBuilder)(nil)
+	_ gBalancer.Picker   = (*balancerPicker)(nil)
+)
+
+func init() {
+	b := base.NewBalancerBuilder(
+		balancerName,
+		&balancerBuilder{},
+		base.Config{HealthCheck: true},
+	)
+	gBalancer.Register(b)
+}
+
+type balancerBuilder struct{}
+
+// Build creates a grpc Picker, 每次连接状态变化时都会重新构建.
+func (b *balancerBuilder) Build(info base.PickerBuildInfo) gBalancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		// Block the RPC until a new picker is available via UpdateState().
+		return base.NewErrPicker(gBalancer.ErrNoSubConnAvailable)
+	}
+	nodes := make([]selector.Node, 0, len(info.ReadySCs))
+	for conn, info := range info.ReadySCs {
+		ins, _ := info.Address.Attributes.Value("rawServiceInstance").(*registry.ServiceInstance)
+		nodes = append(nodes, &grpcNode{
+			Node:    selector.NewNode("grpc", info.Address.Addr, ins),
+			subConn: conn,
+		})
+	}
+	p := &balancerPicker{
+		selector: selector.GlobalSelector().Build(),
+	}
+	p.selector.Apply(nodes)
+	return p
+}
+
+// balancerPicker is a grpc picker.
+type balancerPicker struct {
+	selector selector.Selector
+}
+
+// Pick pick instances.
+func (p *balancerPicker) Pick(info gBalancer.PickInfo) (gBalancer.PickResult, error) {
+	var filters []selector.NodeFilter
+	if tr, ok := transport.FromClientContext(info.Ctx); ok {
+		if gtr, ok := tr.(*Transport); ok {
+			filters = gtr.NodeFilters()
+		}
+	}
+
+	n, done, err := p.selector.Select(info.Ctx, selector.WithNodeFilter(filters...))
+	if err != nil {
+		return gBalancer.PickResult{}, err
+	}
+
+	return gBalancer.PickResult{
+		SubConn: n.(*grpcNode).subConn,
+		Done: func(di gBalancer.DoneInfo) {
+			done(info.Ctx, selector.DoneInfo{
+				Err:           di.Err,
+				BytesSent:     di.BytesSent,
+				BytesReceived: di.BytesReceived,
+				ReplyMD:       Trailer(di.Trailer),
+			})
+		},
+	}, nil
+}
+
+// Trailer is a grpc trailer MD.
+type Trailer metadata.MD
+
+// Get get a grpc trailer value.
+func (t Trailer) Get(k string) string {
+	v := metadata.MD(t).Get(k)
+	if len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+type grpcNode struct {
+	selector.Node
+	subConn gBalancer.SubConn
+}
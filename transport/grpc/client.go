@@ -0,0 +1,309 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"kratos_c/errors"
+	"kratos_c/middleware"
+	"kratos_c/registry"
+	"kratos_c/selector"
+	"kratos_c/selector/wrr"
+	"kratos_c/transport"
+	"kratos_c/transport/grpc/resolver/discovery"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	grpcinsecure "google.golang.org/grpc/credentials/insecure"
+	grpcmd "google.golang.org/grpc/metadata"
+
+	// 注册客户端健康检查
+	_ "google.golang.org/grpc/health"
+)
+
+func init() {
+	if selector.GlobalSelector() == nil {
+		selector.SetGlobalSelector(wrr.NewBuilder())
+	}
+}
+
+// ClientOption is gRPC client option.
+type ClientOption func(o *clientOptions)
+
+// WithEndpoint with client endpoint, 例如 "127.0.0.1:9000" 或 "discovery:///helloworld".
+func WithEndpoint(endpoint string) ClientOption {
+	return func(o *clientOptions) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithTimeout with client timeout.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithMiddleware with client middleware.
+func WithMiddleware(m ...middleware.Middleware) ClientOption {
+	return func(o *clientOptions) {
+		o.middleware = m
+	}
+}
+
+// WithStreamMiddleware with client stream middleware, 作用于 stream 上的每一条 SendMsg/RecvMsg 消息,
+// 约定和服务端的 StreamMiddleware 相同. WithMiddleware 的中间件只作用于建立 stream 的过程.
+func WithStreamMiddleware(m ...middleware.Middleware) ClientOption {
+	return func(o *clientOptions) {
+		o.streamMiddleware = m
+	}
+}
+
+// WithDiscovery with client discovery.
+func WithDiscovery(d registry.Discovery) ClientOption {
+	return func(o *clientOptions) {
+		o.discovery = d
+	}
+}
+
+// WithTLSConfig with TLS config.
+func WithTLSConfig(c *tls.Config) ClientOption {
+	return func(o *clientOptions) {
+		o.tlsConf = c
+	}
+}
+
+// WithUnaryInterceptor returns a DialOption that specifies the interceptor for unary RPCs.
+func WithUnaryInterceptor(in ...grpc.UnaryClientInterceptor) ClientOption {
+	return func(o *clientOptions) {
+		o.ints = in
+	}
+}
+
+// WithStreamInterceptor returns a DialOption that specifies the interceptor for streaming RPCs.
+func WithStreamInterceptor(in ...grpc.StreamClientInterceptor) ClientOption {
+	return func(o *clientOptions) {
+		o.streamInts = in
+	}
+}
+
+// WithOptions with gRPC options.
+func WithOptions(opts ...grpc.DialOption) ClientOption {
+	return func(o *clientOptions) {
+		o.grpcOpts = opts
+	}
+}
+
+// WithNodeFilter with select filters
+func WithNodeFilter(filters ...selector.NodeFilter) ClientOption {
+	return func(o *clientOptions) {
+		o.filters = filters
+	}
+}
+
+// WithHealthCheck with health check
+func WithHealthCheck(healthCheck bool) ClientOption {
+	return func(o *clientOptions) {
+		o.healthCheckConfig = ""
+		if healthCheck {
+			o.healthCheckConfig = `,"healthCheckConfig":{"serviceName":""}`
+		}
+	}
+}
+
+// clientOptions is gRPC Client
+type clientOptions struct {
+	endpoint          string
+	tlsConf           *tls.Config
+	timeout           time.Duration
+	discovery         registry.Discovery
+	middleware        []middleware.Middleware
+	streamMiddleware  []middleware.Middleware
+	ints              []grpc.UnaryClientInterceptor
+	streamInts        []grpc.StreamClientInterceptor
+	grpcOpts          []grpc.DialOption
+	balancerName      string
+	filters           []selector.NodeFilter
+	healthCheckConfig string
+}
+
+// Dial returns a GRPC connection.
+// 连接在第一次调用时才建立, ctx 不会影响连接的生命周期.
+func Dial(_ context.Context, opts ...ClientOption) (*grpc.ClientConn, error) {
+	return dial(false, opts...)
+}
+
+// DialInsecure returns an insecure GRPC connection.
+func DialInsecure(_ context.Context, opts ...ClientOption) (*grpc.ClientConn, error) {
+	return dial(true, opts...)
+}
+
+func dial(insecure bool, opts ...ClientOption) (*grpc.ClientConn, error) {
+	options := clientOptions{
+		timeout:           2000 * time.Millisecond,
+		balancerName:      balancerName,
+		healthCheckConfig: `,"healthCheckConfig":{"serviceName":""}`,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	ints := []grpc.UnaryClientInterceptor{
+		unaryClientInterceptor(options.middleware, options.timeout, options.filters),
+	}
+	sints := []grpc.StreamClientInterceptor{
+		streamClientInterceptor(options.middleware, options.streamMiddleware, options.filters),
+	}
+
+	if len(options.ints) > 0 {
+		ints = append(ints, options.ints...)
+	}
+	if len(options.streamInts) > 0 {
+		sints = append(sints, options.streamInts...)
+	}
+	grpcOpts := []grpc.DialOption{
+		grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"loadBalancingConfig": [{"%s":{}}]%s}`,
+			options.balancerName, options.healthCheckConfig)),
+		grpc.WithChainUnaryInterceptor(ints...),
+		grpc.WithChainStreamInterceptor(sints...),
+	}
+	if options.discovery != nil {
+		grpcOpts = append(grpcOpts,
+			grpc.WithResolvers(
+				discovery.NewBuilder(
+					options.discovery,
+					discovery.WithInsecure(insecure),
+				)))
+	}
+	if insecure {
+		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(grpcinsecure.NewCredentials()))
+	}
+	if options.tlsConf != nil {
+		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(credentials.NewTLS(options.tlsConf)))
+	}
+	if len(options.grpcOpts) > 0 {
+		grpcOpts = append(grpcOpts, options.grpcOpts...)
+	}
+	return grpc.NewClient(options.endpoint, grpcOpts...)
+}
+
+func unaryClientInterceptor(ms []middleware.Middleware, timeout time.Duration, filters []selector.NodeFilter) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		tr := &Transport{
+			endpoint:    cc.Target(),
+			operation:   method,
+			reqHeader:   headerCarrier{},
+			replyHeader: headerCarrier{},
+			nodeFilters: filters,
+		}
+		ctx = transport.NewClientContext(ctx, tr)
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		h := func(ctx context.Context, req any) (any, error) {
+			if ctr, ok := transport.FromClientContext(ctx); ok {
+				ctx = appendOutgoing(ctx, ctr.RequestHeader())
+			}
+			var header grpcmd.MD
+			err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Header(&header))...)
+			// 把服务端返回的 header 交给中间件
+			for k, v := range header {
+				tr.replyHeader[k] = v
+			}
+			if err != nil {
+				// 统一转换为 *errors.Error, 业务错误可以通过 Reason 区分
+				return nil, errors.FromError(err)
+			}
+			return reply, nil
+		}
+		if len(ms) > 0 {
+			h = middleware.Chain(ms...)(h)
+		}
+		var p selector.Peer
+		ctx = selector.NewPeerContext(ctx, &p)
+		_, err := h(ctx, req)
+		return err
+	}
+}
+
+func streamClientInterceptor(ms, streamMs []middleware.Middleware, filters []selector.NodeFilter) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		tr := &Transport{
+			endpoint:    cc.Target(),
+			operation:   method,
+			reqHeader:   headerCarrier{},
+			replyHeader: headerCarrier{},
+			nodeFilters: filters,
+		}
+		ctx = transport.NewClientContext(ctx, tr)
+		// 中间件作用于建立 stream 的过程, 请求头在建立时随 outgoing metadata 发出
+		h := func(ctx context.Context, _ any) (any, error) {
+			if ctr, ok := transport.FromClientContext(ctx); ok {
+				ctx = appendOutgoing(ctx, ctr.RequestHeader())
+			}
+			cs, err := streamer(ctx, desc, cc, method, opts...)
+			if err != nil {
+				return nil, errors.FromError(err)
+			}
+			return cs, nil
+		}
+		if len(ms) > 0 {
+			h = middleware.Chain(ms...)(h)
+		}
+		var p selector.Peer
+		ctx = selector.NewPeerContext(ctx, &p)
+		reply, err := h(ctx, desc)
+		if err != nil {
+			return nil, err
+		}
+		cs, ok := reply.(grpc.ClientStream)
+		if !ok {
+			return nil, errors.InternalServer("STREAM", fmt.Sprintf("client middleware returned %T instead of grpc.ClientStream", reply))
+		}
+		if len(streamMs) == 0 {
+			return cs, nil
+		}
+		return &wrappedClientStream{ClientStream: cs, ctx: ctx, middleware: streamMs}, nil
+	}
+}
+
+// wrappedClientStream 包装 grpc.ClientStream, 让中间件可以观察每一条 SendMsg/RecvMsg 消息.
+type wrappedClientStream struct {
+	grpc.ClientStream
+	ctx        context.Context
+	middleware []middleware.Middleware
+}
+
+// SendMsg 先经过 stream 中间件, 中间件可以替换要发送的消息.
+func (w *wrappedClientStream) SendMsg(m any) error {
+	_, err := middleware.Chain(w.middleware...)(func(_ context.Context, req any) (any, error) {
+		return req, w.ClientStream.SendMsg(req)
+	})(w.ctx, m)
+	return err
+}
+
+// RecvMsg 先经过 stream 中间件, 消息直接填充到 m 中, 中间件返回的替换消息会被忽略.
+func (w *wrappedClientStream) RecvMsg(m any) error {
+	_, err := middleware.Chain(w.middleware...)(func(_ context.Context, req any) (any, error) {
+		return req, w.ClientStream.RecvMsg(m)
+	})(w.ctx, m)
+	return err
+}
+
+// appendOutgoing 将 transport 请求头写入 gRPC 的 outgoing metadata
+func appendOutgoing(ctx context.Context, header transport.Header) context.Context {
+	keys := header.Keys()
+	keyvals := make([]string, 0, len(keys))
+	for _, k := range keys {
+		for _, v := range header.Values(k) {
+			keyvals = append(keyvals, k, v)
+		}
+	}
+	if len(keyvals) == 0 {
+		return ctx
+	}
+	return grpcmd.AppendToOutgoingContext(ctx, keyvals...)
+}
@@ -0,0 +1,162 @@
+package grpc
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"kratos_c/middleware"
+	"kratos_c/registry"
+	"kratos_c/registry/memory"
+	"kratos_c/transport"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// startServer 在随机端口上启动服务, 测试结束时关闭
+func startServer(t *testing.T, opts ...ServerOption) *Server {
+	t.Helper()
+	srv := NewServer(append([]ServerOption{Address("127.0.0.1:0")}, opts...)...)
+	if _, err := srv.Endpoint(); err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = srv.Start(context.Background()) }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = srv.Stop(ctx)
+	})
+	return srv
+}
+
+func dialServer(t *testing.T, srv *Server, opts ...ClientOption) healthpb.HealthClient {
+	t.Helper()
+	e, err := srv.Endpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := DialInsecure(context.Background(), append([]ClientOption{WithEndpoint(e.Host)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+// echoHeader 把请求头 x-client 的值通过响应头 x-server 返回
+func echoHeader(handler middleware.Handler) middleware.Handler {
+	return func(ctx context.Context, req any) (any, error) {
+		if tr, ok := transport.FromServerContext(ctx); ok {
+			tr.ReplyHeader().Set("x-server", tr.RequestHeader().Get("x-client"))
+		}
+		return handler(ctx, req)
+	}
+}
+
+func TestClient(t *testing.T) {
+	srv := startServer(t, Middleware(echoHeader))
+	var operation, reply string
+	client := dialServer(t, srv, WithMiddleware(func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			tr, _ := transport.FromClientContext(ctx)
+			operation = tr.Operation()
+			tr.RequestHeader().Set("x-client", "hello")
+			res, err := handler(ctx, req)
+			reply = tr.ReplyHeader().Get("x-server")
+			return res, err
+		}
+	}))
+	res, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("status = %v, want SERVING", res.Status)
+	}
+	if operation != healthpb.Health_Check_FullMethodName {
+		t.Errorf("operation = %q", operation)
+	}
+	if reply != "hello" {
+		t.Errorf("reply header = %q, want hello", reply)
+	}
+}
+
+func TestClientStream(t *testing.T) {
+	var header atomic.Value
+	srv := startServer(t, StreamMiddleware(func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			if tr, ok := transport.FromServerContext(ctx); ok {
+				header.Store(tr.RequestHeader().Get("x-client"))
+			}
+			return handler(ctx, req)
+		}
+	}))
+	var sent, received atomic.Int32
+	client := dialServer(t, srv,
+		WithMiddleware(func(handler middleware.Handler) middleware.Handler {
+			return func(ctx context.Context, req any) (any, error) {
+				tr, _ := transport.FromClientContext(ctx)
+				tr.RequestHeader().Set("x-client", "stream")
+				return handler(ctx, req)
+			}
+		}),
+		WithStreamMiddleware(func(handler middleware.Handler) middleware.Handler {
+			return func(ctx context.Context, req any) (any, error) {
+				if _, ok := transport.FromClientContext(ctx); !ok {
+					t.Errorf("stream middleware should see the client transport")
+				}
+				switch req.(type) {
+				case *healthpb.HealthCheckRequest:
+					sent.Add(1)
+				case *healthpb.HealthCheckResponse:
+					received.Add(1)
+				}
+				return handler(ctx, req)
+			}
+		}),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("status = %v, want SERVING", res.Status)
+	}
+	if sent.Load() != 1 || received.Load() != 1 {
+		t.Errorf("stream middleware saw %d sent and %d received messages, want 1 and 1", sent.Load(), received.Load())
+	}
+	if header.Load() != "stream" {
+		t.Errorf("server saw request header %v, want stream", header.Load())
+	}
+}
+
+func TestClientDiscovery(t *testing.T) {
+	srv := startServer(t)
+	e, err := srv.Endpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := memory.New()
+	defer r.Close()
+	ins := &registry.ServiceInstance{ID: "1", Name: "helloworld", Endpoints: []string{e.String()}}
+	if err = r.Register(context.Background(), ins); err != nil {
+		t.Fatal(err)
+	}
+	conn, err := DialInsecure(context.Background(), WithEndpoint("discovery:///helloworld"), WithDiscovery(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if _, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+}
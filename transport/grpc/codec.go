@@ -0,0 +1,47 @@
+package grpc
+
+import (
+	"fmt"
+
+	enc "kratos_c/encoding"
+
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/proto"
+)
+
+// RegisterCodec 把 encoding 包中注册的 name 对应的 Codec 注册为 gRPC 的 Codec,
+// 客户端通过 content-subtype 协商, 例如 application/grpc+json.
+// gRPC 的 Codec 是进程全局的, 会覆盖同名的 Codec, 需要在启动 Server 和 Client 之前显式调用:
+//
+//	grpc.RegisterCodec(json.Name)
+func RegisterCodec(name string) {
+	if enc.GetCodec(name) == nil {
+		panic(fmt.Sprintf("grpc: codec %q is not registered in encoding", name))
+	}
+	encoding.RegisterCodec(codec{name: name})
+}
+
+// codec 将 encoding 包中注册的 Codec 适配为 gRPC 的 Codec, 只允许编解码 proto 消息.
+type codec struct {
+	name string
+}
+
+func (c codec) Marshal(v any) ([]byte, error) {
+	vv, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("failed to marshal, message is %T, want proto.Message", v)
+	}
+	return enc.GetCodec(c.name).Marshal(vv)
+}
+
+func (c codec) Unmarshal(data []byte, v any) error {
+	vv, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal, message is %T, want proto.Message", v)
+	}
+	return enc.GetCodec(c.name).Unmarshal(data, vv)
+}
+
+func (c codec) Name() string {
+	return c.name
+}
@@ -0,0 +1,62 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"kratos_c/encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestRegisterCodec(t *testing.T) {
+	RegisterCodec(json.Name)
+	c := encoding.GetCodec(json.Name)
+	if c == nil {
+		t.Fatalf("codec %q is not registered", json.Name)
+	}
+
+	srv := startServer(t)
+	client := dialServer(t, srv)
+	// 通过 content-subtype 使用 json 编解码
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}, grpc.CallContentSubtype(json.Name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("got status %s, want SERVING", resp.GetStatus())
+	}
+}
+
+func TestCodec(t *testing.T) {
+	c := codec{name: json.Name}
+	data, err := c.Marshal(&healthpb.HealthCheckRequest{Service: "helloworld"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var in healthpb.HealthCheckRequest
+	if err = c.Unmarshal(data, &in); err != nil {
+		t.Fatal(err)
+	}
+	if in.GetService() != "helloworld" {
+		t.Errorf("got service %q, want %q", in.GetService(), "helloworld")
+	}
+	// 只允许 proto 消息
+	if _, err = c.Marshal(struct{}{}); err == nil {
+		t.Error("marshal a non proto message: got nil error")
+	}
+	if err = c.Unmarshal(data, &struct{}{}); err == nil {
+		t.Error("unmarshal into a non proto message: got nil error")
+	}
+}
+
+func TestRegisterUnknownCodec(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("registering an unknown codec did not panic")
+		}
+	}()
+	RegisterCodec("unknown")
+}
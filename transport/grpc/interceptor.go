@@ -42,3 +42,86 @@ func (s *Server) unaryServerInterceptor() grpc.UnaryServerInterceptor {
 		return reply, err
 	}
 }
+
+func (s *Server) streamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := ic.Merge(ss.Context(), s.baseCtx)
+		defer cancel()
+		md, _ := metadata.FromIncomingContext(ctx)
+		replyHeader := metadata.MD{}
+		tr := &Transport{
+			operation:   info.FullMethod,
+			reqHeader:   headerCarrier(md),
+			replyHeader: headerCarrier(replyHeader),
+		}
+		if s.endpoint != nil {
+			tr.endpoint = s.endpoint.String()
+		}
+		ctx = transport.NewServerContext(ctx, tr)
+		if s.timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, s.timeout)
+			defer cancel()
+		}
+		ws := &wrappedStream{
+			ServerStream: ss,
+			ctx:          ctx,
+			replyHeader:  replyHeader,
+			middleware:   s.streamMiddleware.Match(tr.Operation()),
+		}
+		err := handler(srv, ws)
+		// 没有发送过消息时 header 还在, 随 trailer 之前一起发出
+		ws.flushHeader()
+		return err
+	}
+}
+
+// wrappedStream 包装 grpc.ServerStream, 让中间件可以观察和修改每一条 RecvMsg/SendMsg 消息.
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx         context.Context
+	replyHeader metadata.MD
+	headerSent  bool
+	middleware  []middleware.Middleware
+}
+
+// Context returns the context with transport and baseCtx merged.
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
+}
+
+// SendMsg 先经过 stream 中间件, 中间件可以替换要发送的消息.
+func (w *wrappedStream) SendMsg(m any) error {
+	h := func(_ context.Context, req any) (any, error) {
+		// 第一条消息会带出 header, 之后再设置 reply header 无效
+		w.flushHeader()
+		return req, w.ServerStream.SendMsg(req)
+	}
+	if len(w.middleware) > 0 {
+		h = middleware.Chain(w.middleware...)(h)
+	}
+	_, err := h(w.ctx, m)
+	return err
+}
+
+// RecvMsg 先经过 stream 中间件, 消息直接填充到 m 中, 中间件在 next 返回后可以看到已经填充的消息,
+// 返回的替换消息会被忽略.
+func (w *wrappedStream) RecvMsg(m any) error {
+	h := func(_ context.Context, req any) (any, error) {
+		return req, w.ServerStream.RecvMsg(m)
+	}
+	if len(w.middleware) > 0 {
+		h = middleware.Chain(w.middleware...)(h)
+	}
+	_, err := h(w.ctx, m)
+	return err
+}
+
+func (w *wrappedStream) flushHeader() {
+	if w.headerSent {
+		return
+	}
+	w.headerSent = true
+	if len(w.replyHeader) > 0 {
+		_ = w.ServerStream.SetHeader(w.replyHeader)
+	}
+}
@@ -0,0 +1,197 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"kratos_c/middleware"
+	"kratos_c/transport"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// testServerStream 记录发送的消息, 以及每条消息发送时已经设置的 header.
+type testServerStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	recv    []string
+	header  metadata.MD
+	sent    []string
+	headers []metadata.MD
+}
+
+func (s *testServerStream) Context() context.Context { return s.ctx }
+
+func (s *testServerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *testServerStream) SendMsg(m any) error {
+	s.sent = append(s.sent, *m.(*string))
+	s.headers = append(s.headers, s.header.Copy())
+	return nil
+}
+
+func (s *testServerStream) RecvMsg(m any) error {
+	if len(s.recv) == 0 {
+		return errors.New("EOF")
+	}
+	*m.(*string), s.recv = s.recv[0], s.recv[1:]
+	return nil
+}
+
+func newTestServerStream(recv ...string) *testServerStream {
+	return &testServerStream{
+		ctx:  metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-md-request", "1")),
+		recv: recv,
+	}
+}
+
+func streamInfo() *grpc.StreamServerInfo {
+	return &grpc.StreamServerInfo{FullMethod: "/helloworld.Greeter/SayHelloStream"}
+}
+
+func TestStreamMiddlewareOrder(t *testing.T) {
+	var calls []string
+	trace := func(name string) middleware.Middleware {
+		return func(next middleware.Handler) middleware.Handler {
+			return func(ctx context.Context, req any) (any, error) {
+				calls = append(calls, name+" before "+*req.(*string))
+				reply, err := next(ctx, req)
+				calls = append(calls, name+" after "+*req.(*string))
+				return reply, err
+			}
+		}
+	}
+	srv := NewServer(StreamMiddleware(trace("m1"), trace("m2")))
+	ss := newTestServerStream("ping")
+	err := srv.streamServerInterceptor()(nil, ss, streamInfo(), func(_ any, stream grpc.ServerStream) error {
+		var in string
+		if err := stream.RecvMsg(&in); err != nil {
+			return err
+		}
+		out := "pong"
+		return stream.SendMsg(&out)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// RecvMsg 时中间件进入时看到的是空消息, next 返回后看到已经填充的消息
+	want := []string{
+		"m1 before ", "m2 before ", "m2 after ping", "m1 after ping",
+		"m1 before pong", "m2 before pong", "m2 after pong", "m1 after pong",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("got calls %q, want %q", calls, want)
+	}
+}
+
+func TestStreamMiddlewareReplace(t *testing.T) {
+	replace := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			msg := "replaced"
+			return next(ctx, &msg)
+		}
+	}
+	srv := NewServer(StreamMiddleware(replace))
+	ss := newTestServerStream("ping")
+	var in string
+	err := srv.streamServerInterceptor()(nil, ss, streamInfo(), func(_ any, stream grpc.ServerStream) error {
+		if err := stream.RecvMsg(&in); err != nil {
+			return err
+		}
+		out := "pong"
+		return stream.SendMsg(&out)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// SendMsg 发送替换后的消息, RecvMsg 的替换被忽略, 消息仍然填充到 handler 传入的 m 中
+	if want := []string{"replaced"}; !reflect.DeepEqual(ss.sent, want) {
+		t.Errorf("got sent %q, want %q", ss.sent, want)
+	}
+	if in != "ping" {
+		t.Errorf("got received %q, want %q", in, "ping")
+	}
+}
+
+func TestStreamHeaderFlush(t *testing.T) {
+	srv := NewServer()
+	ss := newTestServerStream()
+	err := srv.streamServerInterceptor()(nil, ss, streamInfo(), func(_ any, stream grpc.ServerStream) error {
+		tr, _ := transport.FromServerContext(stream.Context())
+		tr.ReplyHeader().Set("x-md-first", "1")
+		for _, msg := range []string{"a", "b"} {
+			if err := stream.SendMsg(&msg); err != nil {
+				return err
+			}
+			// 第一条消息之后设置的 header 不会再发出
+			tr.ReplyHeader().Set("x-md-late", "1")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ss.headers) != 2 {
+		t.Fatalf("got %d messages, want 2", len(ss.headers))
+	}
+	for i, md := range ss.headers {
+		if got := md.Get("x-md-first"); len(got) != 1 {
+			t.Errorf("message %d: header x-md-first = %q, want it before the first send", i, got)
+		}
+	}
+	if got := ss.header.Get("x-md-late"); len(got) != 0 {
+		t.Errorf("header x-md-late = %q, want it dropped after the first send", got)
+	}
+
+	// 没有发送过消息时 header 在 handler 返回后发出
+	ss = newTestServerStream()
+	err = srv.streamServerInterceptor()(nil, ss, streamInfo(), func(_ any, stream grpc.ServerStream) error {
+		tr, _ := transport.FromServerContext(stream.Context())
+		tr.ReplyHeader().Set("x-md-end", "1")
+		if ss.header != nil {
+			t.Errorf("header sent before the handler returned: %v", ss.header)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ss.header.Get("x-md-end"); len(got) != 1 {
+		t.Errorf("header x-md-end = %q, want it flushed at the end", got)
+	}
+}
+
+func TestStreamContext(t *testing.T) {
+	srv := NewServer(Timeout(time.Minute))
+	ss := newTestServerStream()
+	err := srv.streamServerInterceptor()(nil, ss, streamInfo(), func(_ any, stream grpc.ServerStream) error {
+		ctx := stream.Context()
+		tr, ok := transport.FromServerContext(ctx)
+		if !ok {
+			t.Fatal("stream context has no server transport")
+		}
+		if tr.Kind() != transport.KindGRPC {
+			t.Errorf("got kind %s, want %s", tr.Kind(), transport.KindGRPC)
+		}
+		if tr.Operation() != streamInfo().FullMethod {
+			t.Errorf("got operation %q, want %q", tr.Operation(), streamInfo().FullMethod)
+		}
+		if got := tr.RequestHeader().Get("x-md-request"); got != "1" {
+			t.Errorf("got request header %q, want %q", got, "1")
+		}
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("stream context has no deadline, want the server timeout applied")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
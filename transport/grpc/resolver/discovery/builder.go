@@ -0,0 +1,92 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"kratos_c/registry"
+
+	"google.golang.org/grpc/resolver"
+)
+
+const name = "discovery"
+
+// Option is builder option.
+type Option func(o *builder)
+
+// WithTimeout with timeout option.
+func WithTimeout(timeout time.Duration) Option {
+	return func(b *builder) {
+		b.timeout = timeout
+	}
+}
+
+// WithInsecure with isSecure option.
+func WithInsecure(insecure bool) Option {
+	return func(b *builder) {
+		b.insecure = insecure
+	}
+}
+
+type builder struct {
+	discoverer registry.Discovery
+	timeout    time.Duration
+	insecure   bool
+}
+
+// NewBuilder creates a builder which is used to factory registry resolvers.
+// 注册中心返回空列表或者没有可用的 grpc 端点时, resolver 会打印警告并保留之前的地址, 不会清空连接.
+func NewBuilder(d registry.Discovery, opts ...Option) resolver.Builder {
+	b := &builder{
+		discoverer: d,
+		timeout:    time.Second * 10,
+	}
+	for _, o := range opts {
+		o(b)
+	}
+	return b
+}
+
+func (b *builder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	watchRes := &struct {
+		err error
+		w   registry.Watcher
+	}{}
+	done := make(chan struct{}, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		// discovery:///helloworld
+		w, err := b.discoverer.Watch(ctx, strings.TrimPrefix(target.URL.Path, "/"))
+		watchRes.w = w
+		watchRes.err = err
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+		err = watchRes.err
+	case <-time.After(b.timeout):
+		err = errors.New("discovery create watcher overtime")
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	r := &discoveryResolver{
+		w:        watchRes.w,
+		cc:       cc,
+		ctx:      ctx,
+		cancel:   cancel,
+		insecure: b.insecure,
+	}
+	go r.watch()
+	return r, nil
+}
+
+// Scheme return scheme of discovery
+func (*builder) Scheme() string {
+	return name
+}
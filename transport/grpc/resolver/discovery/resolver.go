@@ -0,0 +1,94 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"kratos_c/internal/endpoint"
+	"kratos_c/log"
+	"kratos_c/registry"
+
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/resolver"
+)
+
+type discoveryResolver struct {
+	w  registry.Watcher
+	cc resolver.ClientConn
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	insecure bool
+}
+
+func (r *discoveryResolver) watch() {
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		default:
+		}
+		ins, err := r.w.Next()
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			r.cc.ReportError(fmt.Errorf("[resolver] failed to watch discovery endpoint: %w", err))
+			time.Sleep(time.Second)
+			continue
+		}
+		r.update(ins)
+	}
+}
+
+func (r *discoveryResolver) update(ins []*registry.ServiceInstance) {
+	var (
+		endpoints = make(map[string]struct{})
+		addrs     = make([]resolver.Address, 0, len(ins))
+	)
+	for _, in := range ins {
+		ept, err := endpoint.ParseEndpoint(in.Endpoints, endpoint.Scheme("grpc", !r.insecure))
+		if err != nil || ept == "" {
+			continue
+		}
+		// 多个实例可能注册了相同的地址
+		if _, ok := endpoints[ept]; ok {
+			continue
+		}
+		endpoints[ept] = struct{}{}
+		addr := resolver.Address{
+			ServerName: in.Name,
+			Attributes: parseAttributes(in.Metadata).WithValue("rawServiceInstance", in),
+			Addr:       ept,
+		}
+		addrs = append(addrs, addr)
+	}
+	// 没有可用地址时保留之前的地址, 避免注册中心抖动返回的空列表导致所有请求失败.
+	// 代价是实例全部下线后旧地址仍然可以路由, 直到注册中心返回新的非空列表, 由连接失败和健康检查摘除.
+	if len(addrs) == 0 {
+		log.Warnf("[resolver] zero endpoint found, keep the previous addresses, instances: %d", len(ins))
+		return
+	}
+	if err := r.cc.UpdateState(resolver.State{Addresses: addrs}); err != nil {
+		r.cc.ReportError(fmt.Errorf("[resolver] failed to update state: %w", err))
+	}
+}
+
+// Close resolver
+func (r *discoveryResolver) Close() {
+	r.cancel()
+	_ = r.w.Stop()
+}
+
+// ResolveNow resolver now
+func (r *discoveryResolver) ResolveNow(_ resolver.ResolveNowOptions) {}
+
+func parseAttributes(md map[string]string) (a *attributes.Attributes) {
+	for k, v := range md {
+		a = a.WithValue(k, v)
+	}
+	return a
+}
@@ -0,0 +1,191 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"kratos_c/registry"
+
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/serviceconfig"
+)
+
+type testClientConn struct {
+	resolver.ClientConn
+
+	mu     sync.Mutex
+	states []resolver.State
+	errs   []error
+	update chan struct{}
+}
+
+func newTestClientConn() *testClientConn {
+	return &testClientConn{update: make(chan struct{}, 16)}
+}
+
+func (c *testClientConn) UpdateState(s resolver.State) error {
+	c.mu.Lock()
+	c.states = append(c.states, s)
+	c.mu.Unlock()
+	c.update <- struct{}{}
+	return nil
+}
+
+func (c *testClientConn) ReportError(err error) {
+	c.mu.Lock()
+	c.errs = append(c.errs, err)
+	c.mu.Unlock()
+}
+
+func (c *testClientConn) ParseServiceConfig(string) *serviceconfig.ParseResult { return nil }
+
+func (c *testClientConn) addrs(i int) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var ret []string
+	for _, a := range c.states[i].Addresses {
+		ret = append(ret, a.Addr)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func instance(id string, endpoints ...string) *registry.ServiceInstance {
+	return &registry.ServiceInstance{ID: id, Name: "helloworld", Endpoints: endpoints, Metadata: map[string]string{"zone": "a"}}
+}
+
+func TestUpdate(t *testing.T) {
+	cc := newTestClientConn()
+	r := &discoveryResolver{cc: cc, insecure: true}
+	r.update([]*registry.ServiceInstance{
+		instance("1", "http://127.0.0.1:8000", "grpc://127.0.0.1:9000"),
+		instance("2", "grpc://127.0.0.1:9001"),
+		// 相同的地址只保留一个
+		instance("3", "grpc://127.0.0.1:9001"),
+		// 没有 grpc 端点的实例被忽略
+		instance("4", "http://127.0.0.1:8002"),
+	})
+	if len(cc.states) != 1 {
+		t.Fatalf("got %d updates, want 1", len(cc.states))
+	}
+	if got, want := cc.addrs(0), []string{"127.0.0.1:9000", "127.0.0.1:9001"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("addresses = %v, want %v", got, want)
+	}
+	a := cc.states[0].Addresses[0]
+	if ins, ok := a.Attributes.Value("rawServiceInstance").(*registry.ServiceInstance); !ok || ins.Name != "helloworld" {
+		t.Errorf("address should carry the raw service instance")
+	}
+	if a.Attributes.Value("zone") != "a" {
+		t.Errorf("address should carry the instance metadata")
+	}
+
+	// 空列表保留之前的地址
+	r.update(nil)
+	r.update([]*registry.ServiceInstance{instance("4", "http://127.0.0.1:8002")})
+	if len(cc.states) != 1 {
+		t.Errorf("empty updates should keep the previous state, got %d updates", len(cc.states))
+	}
+}
+
+func TestUpdateSecure(t *testing.T) {
+	cc := newTestClientConn()
+	r := &discoveryResolver{cc: cc}
+	r.update([]*registry.ServiceInstance{instance("1", "grpc://127.0.0.1:9000", "grpcs://127.0.0.1:9443")})
+	if got, want := cc.addrs(0), []string{"127.0.0.1:9443"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("addresses = %v, want %v", got, want)
+	}
+}
+
+type testWatcher struct {
+	ch      chan []*registry.ServiceInstance
+	ctx     context.Context
+	stopped chan struct{}
+	once    sync.Once
+}
+
+func (w *testWatcher) Next() ([]*registry.ServiceInstance, error) {
+	select {
+	case ins := <-w.ch:
+		return ins, nil
+	case <-w.ctx.Done():
+		return nil, w.ctx.Err()
+	case <-w.stopped:
+		return nil, context.Canceled
+	}
+}
+
+func (w *testWatcher) Stop() error {
+	w.once.Do(func() { close(w.stopped) })
+	return nil
+}
+
+type testDiscovery struct {
+	delay   time.Duration
+	service string
+	w       *testWatcher
+}
+
+func (d *testDiscovery) GetService(context.Context, string) ([]*registry.ServiceInstance, error) {
+	return nil, nil
+}
+
+func (d *testDiscovery) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
+	time.Sleep(d.delay)
+	d.service = serviceName
+	d.w = &testWatcher{ch: make(chan []*registry.ServiceInstance, 1), ctx: ctx, stopped: make(chan struct{})}
+	return d.w, nil
+}
+
+func target(t *testing.T, s string) resolver.Target {
+	t.Helper()
+	u, err := url.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolver.Target{URL: *u}
+}
+
+func TestBuilder(t *testing.T) {
+	d := &testDiscovery{}
+	b := NewBuilder(d, WithInsecure(true))
+	if b.Scheme() != "discovery" {
+		t.Errorf("scheme = %s", b.Scheme())
+	}
+	cc := newTestClientConn()
+	r, err := b.Build(target(t, "discovery:///helloworld"), cc, resolver.BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.service != "helloworld" {
+		t.Errorf("watched service = %q, want helloworld", d.service)
+	}
+	d.w.ch <- []*registry.ServiceInstance{instance("1", "grpc://127.0.0.1:9000")}
+	select {
+	case <-cc.update:
+	case <-time.After(time.Second):
+		t.Fatal("resolver did not update the client conn")
+	}
+	if got := cc.addrs(0); !reflect.DeepEqual(got, []string{"127.0.0.1:9000"}) {
+		t.Errorf("addresses = %v", got)
+	}
+	r.Close()
+	select {
+	case <-d.w.stopped:
+	default:
+		t.Errorf("Close should stop the watcher")
+	}
+}
+
+func TestBuilderTimeout(t *testing.T) {
+	b := NewBuilder(&testDiscovery{delay: 100 * time.Millisecond}, WithTimeout(10*time.Millisecond))
+	_, err := b.Build(target(t, "discovery:///helloworld"), newTestClientConn(), resolver.BuildOptions{})
+	if err == nil || errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want a timeout", err)
+	}
+}
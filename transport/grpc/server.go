@@ -9,15 +9,24 @@ import (
 	"net/url"
 	"time"
 
+	"kratos_c/internal/endpoint"
+	"kratos_c/internal/host"
 	"kratos_c/internal/matcher"
+	"kratos_c/transport"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/admin"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
+var (
+	_ transport.Server     = (*Server)(nil)
+	_ transport.Endpointer = (*Server)(nil)
+)
+
 type Server struct {
 	*grpc.Server
 
@@ -67,7 +76,7 @@ func Endpoint(endpoint *url.URL) ServerOption {
 	}
 }
 
-// Timeout with server timeout.
+// Timeout with server timeout, 同时作用于 unary 请求和整个 stream 的生命周期, 长连接的 stream 需要设置足够大的值或者 0 关闭.
 func Timeout(timeout time.Duration) ServerOption {
 	return func(s *Server) {
 		s.timeout = timeout
@@ -87,6 +96,20 @@ func Middleware(m ...middleware.Middleware) ServerOption {
 	}
 }
 
+// OperationMiddleware 为匹配 selector 的 operation 追加中间件, 排在 Middleware 设置的默认中间件之后.
+// selector 可以是精确的 "/pkg.Service/Method", 末尾带 "*" 的前缀, 段内通配 "/pkg.*/Get*",
+// 或者以 "~" 开头的正则. 精确匹配命中时只追加它自己的中间件, 例如只对业务接口鉴权:
+//
+//	grpc.OperationMiddleware("/helloworld.*", jwt.Server(keyFunc))
+func OperationMiddleware(selector string, m ...middleware.Middleware) ServerOption {
+	return func(s *Server) {
+		s.middleware.Add(selector, m...)
+	}
+}
+
+// StreamMiddleware with server stream middleware, 作用于 stream 上的每一条 RecvMsg/SendMsg 消息.
+// SendMsg 发送中间件传给 next 的消息, 中间件可以替换它; RecvMsg 把消息直接填充到 m 中,
+// 中间件在 next 返回后可以看到填充的内容, 返回的替换消息会被忽略.
 func StreamMiddleware(m ...middleware.Middleware) ServerOption {
 	return func(s *Server) {
 		s.streamMiddleware.Use(m...)
@@ -162,8 +185,15 @@ func NewServer(opts ...ServerOption) *Server {
 	if len(srv.unaryInts) > 0 {
 		unaryInts = append(unaryInts, srv.unaryInts...)
 	}
+	streamInts := []grpc.StreamServerInterceptor{
+		srv.streamServerInterceptor(),
+	}
+	if len(srv.streamInts) > 0 {
+		streamInts = append(streamInts, srv.streamInts...)
+	}
 	grpcOpts := []grpc.ServerOption{
 		grpc.ChainUnaryInterceptor(unaryInts...),
+		grpc.ChainStreamInterceptor(streamInts...),
 	}
 	if srv.tlsConf != nil {
 		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(srv.tlsConf)))
@@ -172,9 +202,78 @@ func NewServer(opts ...ServerOption) *Server {
 		grpcOpts = append(grpcOpts, srv.grpcOpts...)
 	}
 	srv.Server = grpc.NewServer(grpcOpts...)
+	// Start 之前不对外提供服务, Start 时通过 Resume 切换为 SERVING
+	srv.health.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	if !srv.customHealth {
+		healthpb.RegisterHealthServer(srv.Server, srv.health)
+	}
 	if !srv.disableReflection {
 		reflection.Register(srv.Server)
 	}
 	srv.adminClean, _ = admin.Register(srv.Server)
 	return srv
 }
+
+// Endpoint return a real address to registry endpoint.
+// examples:
+//
+//	grpc://127.0.0.1:9000?isSecure=false
+func (s *Server) Endpoint() (*url.URL, error) {
+	if err := s.listenAndEndpoint(); err != nil {
+		return nil, err
+	}
+	return s.endpoint, nil
+}
+
+// Start start the gRPC server.
+func (s *Server) Start(ctx context.Context) error {
+	if err := s.listenAndEndpoint(); err != nil {
+		return err
+	}
+	s.baseCtx = ctx
+	log.Infof("[gRPC] server listening on: %s", s.lis.Addr().String())
+	s.health.Resume()
+	return s.Serve(s.lis)
+}
+
+// Stop stop the gRPC server, 先把健康状态切换为 NOT_SERVING, ctx 超时后强制关闭所有连接.
+func (s *Server) Stop(ctx context.Context) error {
+	log.Info("[gRPC] server stopping")
+	if s.adminClean != nil {
+		s.adminClean()
+	}
+	s.health.Shutdown()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.GracefulStop()
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Warn("[gRPC] server couldn't stop gracefully in time, doing force stop")
+		s.Server.Stop()
+		<-done
+	}
+	return nil
+}
+
+func (s *Server) listenAndEndpoint() error {
+	if s.lis == nil {
+		lis, err := net.Listen(s.network, s.address)
+		if err != nil {
+			s.err = err
+			return err
+		}
+		s.lis = lis
+	}
+	if s.endpoint == nil {
+		addr, err := host.Extract(s.address, s.lis)
+		if err != nil {
+			s.err = err
+			return err
+		}
+		s.endpoint = endpoint.NewEndpoint(endpoint.Scheme("grpc", s.tlsConf != nil), addr)
+	}
+	return s.err
+}
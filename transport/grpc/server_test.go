@@ -0,0 +1,186 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"kratos_c/errors"
+	"kratos_c/middleware/auth/jwt"
+	"kratos_c/middleware/selector"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+// assertError 比较错误的 Message, jwt 的错误 Code 和 Reason 都相同, errors.Is 无法区分.
+func assertError(t *testing.T, method string, err, want error) {
+	t.Helper()
+	if want == nil {
+		if err != nil {
+			t.Errorf("%s: got error %v, want nil", method, err)
+		}
+		return
+	}
+	if err == nil || errors.FromError(err).Message != errors.FromError(want).Message {
+		t.Errorf("%s: got error %v, want %v", method, err, want)
+	}
+}
+
+func testToken(t *testing.T, key []byte) string {
+	t.Helper()
+	token, err := jwtv5.NewWithClaims(jwtv5.SigningMethodHS256, jwtv5.MapClaims{"sub": "user"}).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestOperationMiddleware(t *testing.T) {
+	key := []byte("secret")
+	token := testToken(t, key)
+	srv := NewServer(OperationMiddleware("/helloworld.*", jwt.Server(jwt.HMAC(key))))
+	interceptor := srv.unaryServerInterceptor()
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+
+	tests := []struct {
+		method string
+		md     metadata.MD
+		err    error
+	}{
+		{"/grpc.health.v1.Health/Check", metadata.MD{}, nil},
+		{"/helloworld.Greeter/SayHello", metadata.MD{}, jwt.ErrMissingJwtToken},
+		{"/helloworld.Greeter/SayHello", metadata.Pairs("authorization", "Bearer invalid"), jwt.ErrTokenInvalid},
+		{"/helloworld.Greeter/SayHello", metadata.Pairs("authorization", "Bearer "+token), nil},
+	}
+	for _, tt := range tests {
+		ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+		assertError(t, tt.method, err, tt.err)
+	}
+}
+
+func TestSelectorMiddleware(t *testing.T) {
+	key := []byte("secret")
+	token := testToken(t, key)
+	auth := selector.Server(jwt.Server(jwt.HMAC(key))).
+		Match(func(_ context.Context, operation string) bool {
+			return !strings.HasPrefix(operation, "/grpc.health.v1.")
+		}).
+		Build()
+	srv := NewServer(Middleware(auth))
+	interceptor := srv.unaryServerInterceptor()
+	handler := func(ctx context.Context, _ any) (any, error) {
+		if _, ok := jwt.FromContext(ctx); ok {
+			return "authenticated", nil
+		}
+		return "anonymous", nil
+	}
+
+	tests := []struct {
+		method string
+		md     metadata.MD
+		reply  any
+		err    error
+	}{
+		{"/grpc.health.v1.Health/Check", metadata.MD{}, "anonymous", nil},
+		{"/grpc.health.v1.Health/Check", metadata.Pairs("authorization", "Bearer "+token), "anonymous", nil},
+		{"/helloworld.Greeter/SayHello", metadata.MD{}, nil, jwt.ErrMissingJwtToken},
+		{"/helloworld.Greeter/SayHello", metadata.Pairs("authorization", "Bearer invalid"), nil, jwt.ErrTokenInvalid},
+		{"/helloworld.Greeter/SayHello", metadata.Pairs("authorization", "Bearer "+token), "authenticated", nil},
+	}
+	for _, tt := range tests {
+		ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+		reply, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+		assertError(t, tt.method, err, tt.err)
+		if tt.err == nil && reply != tt.reply {
+			t.Errorf("%s: got reply %v, want %v", tt.method, reply, tt.reply)
+		}
+	}
+}
+
+func TestServerStartStop(t *testing.T) {
+	srv := NewServer(Address(":0"))
+	e, err := srv.Endpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	host, port, err := net.SplitHostPort(e.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		t.Errorf("got endpoint host %q, want a concrete address", host)
+	}
+	if port == "0" {
+		t.Errorf("got endpoint port %q, want the listening port", port)
+	}
+
+	req := &healthpb.HealthCheckRequest{}
+	if resp, _ := srv.health.Check(context.Background(), req); resp.GetStatus() != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("before Start: got status %s, want NOT_SERVING", resp.GetStatus())
+	}
+	started := make(chan error, 1)
+	go func() { started <- srv.Start(context.Background()) }()
+
+	client := dialServer(t, srv)
+	var status healthpb.HealthCheckResponse_ServingStatus
+	for range 50 {
+		if resp, err := client.Check(context.Background(), req); err == nil {
+			if status = resp.GetStatus(); status == healthpb.HealthCheckResponse_SERVING {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("after Start: got status %s, want SERVING", status)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := srv.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-started; err != nil {
+		t.Errorf("Start returned %v after Stop", err)
+	}
+	if resp, _ := srv.health.Check(context.Background(), req); resp.GetStatus() != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("after Stop: got status %s, want NOT_SERVING", resp.GetStatus())
+	}
+}
+
+func TestServerForceStop(t *testing.T) {
+	srv := startServer(t)
+	client := dialServer(t, srv)
+	// Watch 会一直保持 stream, GracefulStop 无法在 ctx 到期前完成
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	done := make(chan error, 1)
+	go func() { done <- srv.Stop(ctx) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop with an expired context did not force stop the server")
+	}
+	for {
+		if _, err := stream.Recv(); err != nil {
+			break
+		}
+	}
+}
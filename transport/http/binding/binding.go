@@ -0,0 +1,74 @@
+package binding
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"kratos_c/encoding"
+	"kratos_c/encoding/form"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// reg 匹配路径模板中的变量, 例如 {id}, {user.id}, {name=users/*}
+var reg = regexp.MustCompile(`{([^}=]+)(=[^}]*)?}`)
+
+// BindQuery bind vars parameters to target.
+func BindQuery(vars url.Values, target any) error {
+	return encoding.GetCodec(form.Name).Unmarshal([]byte(vars.Encode()), target)
+}
+
+// BindForm bind form parameters to target.
+func BindForm(req *http.Request, target any) error {
+	if err := req.ParseForm(); err != nil {
+		return err
+	}
+	return BindQuery(req.Form, target)
+}
+
+// EncodeURL 使用 msg 的字段填充路径模板中的变量, needQuery 为 true 时其余字段编码到 query 中.
+// 没有 body 的请求 needQuery 为 true; 有 body 的请求只会在 query 中追加 FieldMask 字段.
+func EncodeURL(pathTemplate string, msg any, needQuery bool) string {
+	if msg == nil || (reflect.ValueOf(msg).Kind() == reflect.Pointer && reflect.ValueOf(msg).IsNil()) {
+		return pathTemplate
+	}
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return pathTemplate
+	}
+	queryParams, _ := form.EncodeValues(m)
+	pathParams := make(map[string]struct{})
+	path := reg.ReplaceAllStringFunc(pathTemplate, func(in string) string {
+		sub := reg.FindStringSubmatch(in)
+		key := strings.TrimSpace(sub[1])
+		pathParams[key] = struct{}{}
+		value := queryParams.Get(key)
+		if sub[2] == "" {
+			return url.PathEscape(value)
+		}
+		// {name=users/*} 的取值本身包含多个路径段, 分段转义
+		segs := strings.Split(value, "/")
+		for i, seg := range segs {
+			segs[i] = url.PathEscape(seg)
+		}
+		return strings.Join(segs, "/")
+	})
+	if !needQuery {
+		if query := form.EncodeFieldMask(m.ProtoReflect()); query != "" {
+			return path + "?" + query
+		}
+		return path
+	}
+	if len(queryParams) > 0 {
+		for key := range pathParams {
+			delete(queryParams, key)
+		}
+		if query := queryParams.Encode(); query != "" {
+			path += "?" + query
+		}
+	}
+	return path
+}
@@ -0,0 +1,293 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"kratos_c/encoding"
+	"kratos_c/errors"
+	"kratos_c/internal/endpoint"
+	"kratos_c/internal/httputil"
+	"kratos_c/middleware"
+	"kratos_c/transport"
+)
+
+// DecodeErrorFunc is decode error func.
+type DecodeErrorFunc func(ctx context.Context, res *http.Response) error
+
+// EncodeRequestFunc is request encode func.
+type EncodeRequestFunc func(ctx context.Context, contentType string, in any) (body []byte, err error)
+
+// DecodeResponseFunc is response decode func.
+type DecodeResponseFunc func(ctx context.Context, res *http.Response, out any) error
+
+// ClientOption is HTTP client option.
+type ClientOption func(*clientOptions)
+
+// clientOptions is HTTP client options.
+type clientOptions struct {
+	tlsConf      *tls.Config
+	timeout      time.Duration
+	endpoint     string
+	userAgent    string
+	encoder      EncodeRequestFunc
+	decoder      DecodeResponseFunc
+	errorDecoder DecodeErrorFunc
+	transport    http.RoundTripper
+	middleware   []middleware.Middleware
+}
+
+// WithTransport with client transport.
+func WithTransport(trans http.RoundTripper) ClientOption {
+	return func(o *clientOptions) {
+		o.transport = trans
+	}
+}
+
+// WithTimeout with client request timeout.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = d
+	}
+}
+
+// WithUserAgent with client user agent.
+func WithUserAgent(ua string) ClientOption {
+	return func(o *clientOptions) {
+		o.userAgent = ua
+	}
+}
+
+// WithMiddleware with client middleware.
+func WithMiddleware(m ...middleware.Middleware) ClientOption {
+	return func(o *clientOptions) {
+		o.middleware = m
+	}
+}
+
+// WithEndpoint with client addr, 例如 "127.0.0.1:8000" 或 "https://example.com".
+func WithEndpoint(endpoint string) ClientOption {
+	return func(o *clientOptions) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithRequestEncoder with client request encoder.
+func WithRequestEncoder(encoder EncodeRequestFunc) ClientOption {
+	return func(o *clientOptions) {
+		o.encoder = encoder
+	}
+}
+
+// WithResponseDecoder with client response decoder.
+func WithResponseDecoder(decoder DecodeResponseFunc) ClientOption {
+	return func(o *clientOptions) {
+		o.decoder = decoder
+	}
+}
+
+// WithErrorDecoder with client error decoder.
+func WithErrorDecoder(errorDecoder DecodeErrorFunc) ClientOption {
+	return func(o *clientOptions) {
+		o.errorDecoder = errorDecoder
+	}
+}
+
+// WithTLSConfig with tls config.
+func WithTLSConfig(c *tls.Config) ClientOption {
+	return func(o *clientOptions) {
+		o.tlsConf = c
+	}
+}
+
+// Client is an HTTP client.
+type Client struct {
+	opts   clientOptions
+	target *url.URL
+	cc     *http.Client
+}
+
+// NewClient returns an HTTP client.
+func NewClient(_ context.Context, opts ...ClientOption) (*Client, error) {
+	options := clientOptions{
+		timeout:      2000 * time.Millisecond,
+		encoder:      DefaultRequestEncoder,
+		decoder:      DefaultResponseDecoder,
+		errorDecoder: DefaultErrorDecoder,
+		transport:    http.DefaultTransport,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.tlsConf != nil {
+		if tr, ok := options.transport.(*http.Transport); ok {
+			tr = tr.Clone()
+			tr.TLSClientConfig = options.tlsConf
+			options.transport = tr
+		}
+	}
+	target, err := parseTarget(options.endpoint, options.tlsConf == nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		opts:   options,
+		target: target,
+		cc: &http.Client{
+			Timeout:   options.timeout,
+			Transport: options.transport,
+		},
+	}, nil
+}
+
+// Invoke makes a rpc call procedure for remote service.
+func (client *Client) Invoke(ctx context.Context, method, path string, args any, reply any, opts ...CallOption) error {
+	var (
+		contentType string
+		data        []byte
+	)
+	c := defaultCallInfo(path)
+	for _, o := range opts {
+		if err := o.before(&c); err != nil {
+			return err
+		}
+	}
+	if args != nil {
+		var err error
+		data, err = client.opts.encoder(ctx, c.contentType, args)
+		if err != nil {
+			return err
+		}
+		contentType = c.contentType
+	}
+	url := fmt.Sprintf("%s://%s%s", client.target.Scheme, client.target.Host, path)
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", c.contentType)
+	}
+	// 默认期望服务端使用与请求相同的编码响应
+	req.Header.Set("Accept", c.contentType)
+	if client.opts.userAgent != "" {
+		req.Header.Set("User-Agent", client.opts.userAgent)
+	}
+	tr := &Transport{
+		endpoint:     client.opts.endpoint,
+		reqHeader:    headerCarrier(req.Header),
+		replyHeader:  headerCarrier{},
+		operation:    c.operation,
+		request:      req,
+		pathTemplate: c.pathTemplate,
+	}
+	ctx = transport.NewClientContext(ctx, tr)
+	h := func(ctx context.Context, _ any) (any, error) {
+		// 中间件 (例如重试) 可能多次调用, 每次都从编码后的数据重新构造 body
+		attempt := req.WithContext(ctx)
+		attempt.Body, _ = req.GetBody()
+		res, err := client.do(attempt)
+		if res != nil {
+			defer res.Body.Close()
+			// 错误响应也需要把响应头交给调用方
+			tr.replyHeader = headerCarrier(res.Header)
+			cs := csAttempt{res: res}
+			for _, o := range opts {
+				o.after(&c, &cs)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := client.opts.decoder(ctx, res, reply); err != nil {
+			return nil, err
+		}
+		return reply, nil
+	}
+	if len(client.opts.middleware) > 0 {
+		h = middleware.Chain(client.opts.middleware...)(h)
+	}
+	_, err = h(ctx, args)
+	return err
+}
+
+// Do send an HTTP request, 状态码不是 2xx 时返回错误, 同时返回响应以便读取响应头, 调用方负责关闭 body.
+func (client *Client) Do(req *http.Request, opts ...CallOption) (*http.Response, error) {
+	c := defaultCallInfo(req.URL.Path)
+	for _, o := range opts {
+		if err := o.before(&c); err != nil {
+			return nil, err
+		}
+	}
+	return client.do(req)
+}
+
+func (client *Client) do(req *http.Request) (*http.Response, error) {
+	resp, err := client.cc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err = client.opts.errorDecoder(req.Context(), resp); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
+// Close tears down the Transport and all underlying connections.
+func (client *Client) Close() error {
+	client.cc.CloseIdleConnections()
+	return nil
+}
+
+// DefaultRequestEncoder is an HTTP request encoder.
+func DefaultRequestEncoder(_ context.Context, contentType string, v any) ([]byte, error) {
+	codec := encoding.GetCodec(httputil.ContentSubtype(contentType))
+	if codec == nil {
+		return nil, fmt.Errorf("http: unregister Content-Type: %s", contentType)
+	}
+	return codec.Marshal(v)
+}
+
+// DefaultResponseDecoder is an HTTP response decoder.
+func DefaultResponseDecoder(_ context.Context, res *http.Response, v any) error {
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return CodecForResponse(res).Unmarshal(data, v)
+}
+
+// DefaultErrorDecoder is an HTTP error decoder.
+// 响应体可以解析为 errors.Status 时返回对应的 *errors.Error, 否则只保留状态码.
+func DefaultErrorDecoder(_ context.Context, res *http.Response) error {
+	if res.StatusCode >= 200 && res.StatusCode <= 299 {
+		return nil
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err == nil {
+		e := new(errors.Error)
+		if err = CodecForResponse(res).Unmarshal(data, &e.Status); err == nil {
+			e.Code = int32(res.StatusCode)
+			return e
+		}
+	}
+	return errors.Newf(res.StatusCode, errors.UnknownReason, "%s", bytes.TrimSpace(data)).WithCause(err)
+}
+
+func parseTarget(addr string, insecure bool) (*url.URL, error) {
+	if !strings.Contains(addr, "://") {
+		addr = endpoint.Scheme("http", !insecure) + "://" + addr
+	}
+	return url.Parse(addr)
+}
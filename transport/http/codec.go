@@ -0,0 +1,121 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"kratos_c/encoding"
+	"kratos_c/encoding/json"
+	"kratos_c/errors"
+	"kratos_c/internal/httputil"
+	"kratos_c/transport/http/binding"
+
+	_ "kratos_c/encoding/form"
+	_ "kratos_c/encoding/proto"
+	_ "kratos_c/encoding/xml"
+	_ "kratos_c/encoding/yaml"
+)
+
+// DecodeRequestFunc is decode request func.
+type DecodeRequestFunc func(*http.Request, any) error
+
+// EncodeResponseFunc is encode response func.
+type EncodeResponseFunc func(http.ResponseWriter, *http.Request, any) error
+
+// EncodeErrorFunc is encode error func.
+type EncodeErrorFunc func(http.ResponseWriter, *http.Request, error)
+
+// DefaultRequestVars decodes the request vars to object.
+func DefaultRequestVars(r *http.Request, v any) error {
+	if err := binding.BindQuery(Vars(r), v); err != nil {
+		return errors.BadRequest("CODEC", err.Error())
+	}
+	return nil
+}
+
+// DefaultRequestQuery decodes the request query to object.
+func DefaultRequestQuery(r *http.Request, v any) error {
+	if err := binding.BindQuery(r.URL.Query(), v); err != nil {
+		return errors.BadRequest("CODEC", err.Error())
+	}
+	return nil
+}
+
+// DefaultRequestDecoder decodes the request body to object.
+func DefaultRequestDecoder(r *http.Request, v any) error {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return errors.BadRequest("CODEC", err.Error())
+	}
+	// 没有 body 时不需要 Content-Type
+	if len(data) == 0 {
+		return nil
+	}
+	codec, ok := CodecForRequest(r, "Content-Type")
+	if !ok {
+		return errors.BadRequest("CODEC", fmt.Sprintf("unregister Content-Type: %s", r.Header.Get("Content-Type")))
+	}
+	if err = codec.Unmarshal(data, v); err != nil {
+		return errors.BadRequest("CODEC", fmt.Sprintf("body unmarshal %s", err.Error()))
+	}
+	return nil
+}
+
+// DefaultResponseEncoder encodes the object to the HTTP response.
+func DefaultResponseEncoder(w http.ResponseWriter, r *http.Request, v any) error {
+	if v == nil {
+		return nil
+	}
+	if rd, ok := v.(Redirector); ok {
+		url, code := rd.Redirect()
+		http.Redirect(w, r, url, code)
+		return nil
+	}
+	codec, _ := CodecForRequest(r, "Accept")
+	data, err := codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", httputil.ContentType(codec.Name()))
+	_, err = w.Write(data)
+	return err
+}
+
+// DefaultErrorEncoder encodes the error to the HTTP response.
+func DefaultErrorEncoder(w http.ResponseWriter, r *http.Request, err error) {
+	se := errors.FromError(err)
+	codec, _ := CodecForRequest(r, "Accept")
+	body, err := codec.Marshal(&se.Status)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", httputil.ContentType(codec.Name()))
+	// 业务码或 0 不能作为 HTTP 状态码, WriteHeader 会 panic; 1xx 不是最终响应, 同样按 500 处理
+	code := int(se.Code)
+	if code < 200 || code > 999 {
+		code = http.StatusInternalServerError
+	}
+	w.WriteHeader(code)
+	_, _ = w.Write(body)
+}
+
+// CodecForRequest get encoding.Codec via http.Request.
+// 按头部中出现的顺序选择第一个已注册的 Codec, 没有时返回 json 且 ok 为 false.
+func CodecForRequest(r *http.Request, name string) (encoding.Codec, bool) {
+	for _, typ := range httputil.MediaTypes(r.Header.Values(name)) {
+		if codec := encoding.GetCodec(httputil.ContentSubtype(typ)); codec != nil {
+			return codec, true
+		}
+	}
+	return encoding.GetCodec(json.Name), false
+}
+
+// CodecForResponse get encoding.Codec via http.Response.
+func CodecForResponse(r *http.Response) encoding.Codec {
+	if codec := encoding.GetCodec(httputil.ContentSubtype(r.Header.Get("Content-Type"))); codec != nil {
+		return codec
+	}
+	return encoding.GetCodec(json.Name)
+}
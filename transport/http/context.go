@@ -0,0 +1,205 @@
+package http
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"kratos_c/middleware"
+	"kratos_c/transport"
+	"kratos_c/transport/http/binding"
+)
+
+var _ Context = (*wrapper)(nil)
+
+// Context is an HTTP Context.
+type Context interface {
+	context.Context
+	Vars() url.Values
+	Query() url.Values
+	Form() url.Values
+	Header() http.Header
+	Request() *http.Request
+	Response() http.ResponseWriter
+	Middleware(middleware.Handler) middleware.Handler
+	Bind(any) error
+	BindVars(any) error
+	BindQuery(any) error
+	BindForm(any) error
+	Returns(any, error) error
+	Result(int, any) error
+	JSON(int, any) error
+	XML(int, any) error
+	String(int, string) error
+	Blob(int, string, []byte) error
+	Stream(int, string, io.Reader) error
+	Reset(http.ResponseWriter, *http.Request)
+}
+
+// responseWriter 延迟写入状态码, 让 Result 可以在编码响应之前设置状态码.
+// 状态码只在第一次 Write 或 handler 返回后 flush 时写出一次.
+type responseWriter struct {
+	code        int
+	wroteHeader bool
+	w           http.ResponseWriter
+}
+
+func (w *responseWriter) reset(res http.ResponseWriter) {
+	w.w = res
+	w.code = 0
+	w.wroteHeader = false
+}
+func (w *responseWriter) Header() http.Header { return w.w.Header() }
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.code = statusCode
+	}
+}
+func (w *responseWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.w.WriteHeader(cmp.Or(w.code, http.StatusOK))
+	}
+	return w.w.Write(data)
+}
+func (w *responseWriter) Unwrap() http.ResponseWriter { return w.w }
+
+// Flush 写出状态码并把已缓冲的数据发送给客户端, 用于 Stream 等流式响应.
+func (w *responseWriter) Flush() {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.w.WriteHeader(cmp.Or(w.code, http.StatusOK))
+	}
+	if f, ok := w.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// flush 写出只设置了状态码但没有写 body 的响应
+func (w *responseWriter) flush() {
+	if !w.wroteHeader && w.code != 0 {
+		w.wroteHeader = true
+		w.w.WriteHeader(w.code)
+	}
+}
+
+type wrapper struct {
+	router *Router
+	req    *http.Request
+	w      responseWriter
+}
+
+func (c *wrapper) Header() http.Header {
+	return c.req.Header
+}
+
+// Vars 返回路由模板中的路径参数
+func (c *wrapper) Vars() url.Values {
+	return Vars(c.req)
+}
+
+func (c *wrapper) Form() url.Values {
+	if err := c.req.ParseForm(); err != nil {
+		return url.Values{}
+	}
+	return c.req.Form
+}
+
+func (c *wrapper) Query() url.Values {
+	return c.req.URL.Query()
+}
+func (c *wrapper) Request() *http.Request        { return c.req }
+func (c *wrapper) Response() http.ResponseWriter { return &c.w }
+func (c *wrapper) Middleware(h middleware.Handler) middleware.Handler {
+	if tr, ok := transport.FromServerContext(c.req.Context()); ok {
+		return middleware.Chain(c.router.srv.middleware.Match(tr.Operation())...)(h)
+	}
+	// 没有 Transport 时按路由 pattern 匹配, 不使用原始路径, 避免匹配缓存随请求路径无限增长
+	return middleware.Chain(c.router.srv.middleware.Match(c.req.Pattern)...)(h)
+}
+func (c *wrapper) Bind(v any) error      { return c.router.srv.decBody(c.req, v) }
+func (c *wrapper) BindVars(v any) error  { return c.router.srv.decVars(c.req, v) }
+func (c *wrapper) BindQuery(v any) error { return c.router.srv.decQuery(c.req, v) }
+func (c *wrapper) BindForm(v any) error  { return binding.BindForm(c.req, v) }
+func (c *wrapper) Returns(v any, err error) error {
+	if err != nil {
+		return err
+	}
+	return c.router.srv.enc(&c.w, c.req, v)
+}
+
+func (c *wrapper) Result(code int, v any) error {
+	c.w.WriteHeader(code)
+	return c.router.srv.enc(&c.w, c.req, v)
+}
+
+func (c *wrapper) JSON(code int, v any) error {
+	c.w.Header().Set("Content-Type", "application/json")
+	c.w.WriteHeader(code)
+	return json.NewEncoder(&c.w).Encode(v)
+}
+
+func (c *wrapper) XML(code int, v any) error {
+	c.w.Header().Set("Content-Type", "application/xml")
+	c.w.WriteHeader(code)
+	return xml.NewEncoder(&c.w).Encode(v)
+}
+
+func (c *wrapper) String(code int, text string) error {
+	c.w.Header().Set("Content-Type", "text/plain")
+	c.w.WriteHeader(code)
+	_, err := c.w.Write([]byte(text))
+	return err
+}
+
+func (c *wrapper) Blob(code int, contentType string, data []byte) error {
+	c.w.Header().Set("Content-Type", contentType)
+	c.w.WriteHeader(code)
+	_, err := c.w.Write(data)
+	return err
+}
+
+func (c *wrapper) Stream(code int, contentType string, rd io.Reader) error {
+	c.w.Header().Set("Content-Type", contentType)
+	c.w.WriteHeader(code)
+	_, err := io.Copy(&c.w, rd)
+	return err
+}
+
+func (c *wrapper) Reset(res http.ResponseWriter, req *http.Request) {
+	c.w.reset(res)
+	c.req = req
+}
+
+func (c *wrapper) Deadline() (time.Time, bool) {
+	if c.req == nil {
+		return time.Time{}, false
+	}
+	return c.req.Context().Deadline()
+}
+
+func (c *wrapper) Done() <-chan struct{} {
+	if c.req == nil {
+		return nil
+	}
+	return c.req.Context().Done()
+}
+
+func (c *wrapper) Err() error {
+	if c.req == nil {
+		return context.Canceled
+	}
+	return c.req.Context().Err()
+}
+
+func (c *wrapper) Value(key any) any {
+	if c.req == nil {
+		return nil
+	}
+	return c.req.Context().Value(key)
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// recorder 记录 WriteHeader 的调用次数
+type recorder struct {
+	*httptest.ResponseRecorder
+	headers int
+}
+
+func (r *recorder) WriteHeader(code int) {
+	r.headers++
+	r.ResponseRecorder.WriteHeader(code)
+}
+
+func TestResponseWriter(t *testing.T) {
+	srv := NewServer(ResponseEncoder(func(w http.ResponseWriter, _ *http.Request, v any) error {
+		if v == nil {
+			return nil
+		}
+		for _, s := range v.([]string) {
+			if _, err := w.Write([]byte(s)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	r := srv.Route("/")
+	r.GET("/stream", func(ctx Context) error {
+		return ctx.Result(http.StatusAccepted, []string{"a", "b", "c"})
+	})
+	r.GET("/empty", func(ctx Context) error {
+		return ctx.Result(http.StatusNoContent, nil)
+	})
+
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/stream", http.StatusAccepted, "abc"},
+		{"/empty", http.StatusNoContent, ""},
+	}
+	for _, tt := range tests {
+		w := &recorder{ResponseRecorder: httptest.NewRecorder()}
+		srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.code || w.Body.String() != tt.body || w.headers != 1 {
+			t.Errorf("%s: got %d %q with %d WriteHeader calls, want %d %q once", tt.path, w.Code, w.Body.String(), w.headers, tt.code, tt.body)
+		}
+	}
+}
+
+func TestContextWriters(t *testing.T) {
+	srv := NewServer()
+	r := srv.Route("/")
+	r.GET("/json", func(ctx Context) error {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"a": "b"})
+	})
+	r.GET("/xml", func(ctx Context) error {
+		return ctx.XML(http.StatusConflict, struct {
+			XMLName struct{} `xml:"v"`
+			A       string   `xml:"a"`
+		}{A: "b"})
+	})
+	r.GET("/string", func(ctx Context) error {
+		return ctx.String(http.StatusNotFound, "missing")
+	})
+	r.GET("/blob", func(ctx Context) error {
+		return ctx.Blob(http.StatusCreated, "application/octet-stream", []byte{1, 2})
+	})
+	r.GET("/stream", func(ctx Context) error {
+		return ctx.Stream(http.StatusPartialContent, "text/plain", strings.NewReader("part"))
+	})
+	r.GET("/response", func(ctx Context) error {
+		ctx.Response().WriteHeader(http.StatusTeapot)
+		return nil
+	})
+	// Result 设置了状态码但编码失败时, 使用错误的状态码
+	r.GET("/error", func(ctx Context) error {
+		ctx.Response().WriteHeader(http.StatusAccepted)
+		return errors.New("boom")
+	})
+
+	tests := []struct {
+		path        string
+		code        int
+		contentType string
+		body        string
+	}{
+		{"/json", http.StatusBadRequest, "application/json", "{\"a\":\"b\"}\n"},
+		{"/xml", http.StatusConflict, "application/xml", "<v><a>b</a></v>"},
+		{"/string", http.StatusNotFound, "text/plain", "missing"},
+		{"/blob", http.StatusCreated, "application/octet-stream", "\x01\x02"},
+		{"/stream", http.StatusPartialContent, "text/plain", "part"},
+		{"/response", http.StatusTeapot, "", ""},
+		{"/error", http.StatusInternalServerError, "", ""},
+	}
+	for _, tt := range tests {
+		w := &recorder{ResponseRecorder: httptest.NewRecorder()}
+		srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.code || w.headers != 1 {
+			t.Errorf("%s: got %d with %d WriteHeader calls, want %d once", tt.path, w.Code, w.headers, tt.code)
+		}
+		if tt.contentType != "" && (w.Header().Get("Content-Type") != tt.contentType || w.Body.String() != tt.body) {
+			t.Errorf("%s: got %q %q, want %q %q", tt.path, w.Header().Get("Content-Type"), w.Body.String(), tt.contentType, tt.body)
+		}
+	}
+}
+
+// TestResponseRecordsStatus 中间件通过 Response() 拿到的 writer 能看到 handler 写出的状态码
+func TestResponseRecordsStatus(t *testing.T) {
+	srv := NewServer()
+	var code int
+	srv.Route("/").GET("/", func(ctx Context) error {
+		err := ctx.String(http.StatusServiceUnavailable, "down")
+		if rw, ok := ctx.Response().(*responseWriter); ok {
+			code = rw.code
+		}
+		return err
+	})
+	srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("recorded status = %d, want %d", code, http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// templateVarRe 匹配 google.api.http 风格的路径变量, 例如 {id}, {user.id}, {name=shelves/*/books/**}
+var templateVarRe = regexp.MustCompile(`\{([^}=]+)(?:=([^}]*))?\}`)
+
+// identRe 是标准库 ServeMux 允许的路径参数名
+var identRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// verbRe 匹配 google.api.http 模板末尾的自定义方法, 例如 /v1/{name}:cancel 中的 :cancel
+var verbRe = regexp.MustCompile(`\}(:[A-Za-z0-9_.~-]+)$`)
+
+// lastParamRe 匹配 pattern 最后一个路径段的 ServeMux 参数
+var lastParamRe = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)(?:\.\.\.)?\}$`)
+
+// pathVar 描述一个路径变量由哪些片段拼接而成
+type pathVar struct {
+	name string
+	// parts 中以 { 开头的是 ServeMux 的参数名, 其余为字面量
+	parts []string
+}
+
+// parseTemplate 将路由模板转换为 ServeMux 的 pattern, 同时兼容标准库语法和 google.api.http 语法.
+// {user.id} 会被替换为合法的参数名, {name=shelves/*} 会展开为 shelves/{p0}, 取值时再拼回 shelves/xxx.
+func parseTemplate(path string) (string, []pathVar, error) {
+	var (
+		vars []pathVar
+		n    int
+		err  error
+	)
+	next := func() string {
+		n++
+		return fmt.Sprintf("p%d", n-1)
+	}
+	pattern := templateVarRe.ReplaceAllStringFunc(path, func(s string) string {
+		m := templateVarRe.FindStringSubmatch(s)
+		name, sub := strings.TrimSpace(m[1]), m[2]
+		rest := strings.HasSuffix(name, "...")
+		name = strings.TrimSuffix(name, "...")
+		if name == "$" {
+			return s
+		}
+		if sub == "" || sub == "*" {
+			param := name
+			if !identRe.MatchString(param) {
+				param = next()
+			}
+			vars = append(vars, pathVar{name: name, parts: []string{"{" + param}})
+			if rest {
+				return "{" + param + "...}"
+			}
+			return "{" + param + "}"
+		}
+		v := pathVar{name: name}
+		segs := strings.Split(sub, "/")
+		for i, seg := range segs {
+			if i > 0 {
+				v.parts = append(v.parts, "/")
+			}
+			switch seg {
+			case "*":
+				param := next()
+				v.parts = append(v.parts, "{"+param)
+				segs[i] = "{" + param + "}"
+			case "**":
+				if i != len(segs)-1 {
+					err = fmt.Errorf("http: ** must be the last segment of %q", path)
+				}
+				param := next()
+				v.parts = append(v.parts, "{"+param)
+				segs[i] = "{" + param + "...}"
+			default:
+				v.parts = append(v.parts, seg)
+			}
+		}
+		vars = append(vars, v)
+		return strings.Join(segs, "/")
+	})
+	return pattern, vars, err
+}
+
+// splitVerb 拆出变量后面的自定义方法, ServeMux 不允许参数和字面量出现在同一个路径段里,
+// 所以 /v1/{name}:cancel 按 /v1/{name} 注册, 请求时再由 verbMux 校验并去掉 :cancel.
+// 字面量路径段上的自定义方法 (例如 /v1/books:batchGet) 可以直接交给 ServeMux 匹配.
+func splitVerb(path string) (string, string) {
+	if m := verbRe.FindStringSubmatchIndex(path); m != nil {
+		return path[:m[2]], path[m[2]:]
+	}
+	return path, ""
+}
+
+// verbMux 把同一个 pattern 上不同自定义方法的路由分发到各自的 handler
+type verbMux struct {
+	// param 是 pattern 最后一个路径段的参数名, 自定义方法是它的后缀
+	param string
+	verbs map[string]http.Handler
+}
+
+func newVerbMux(pattern string) *verbMux {
+	v := &verbMux{verbs: make(map[string]http.Handler)}
+	if m := lastParamRe.FindStringSubmatch(pattern); m != nil {
+		v.param = m[1]
+	}
+	return v
+}
+
+func (v *verbMux) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if len(v.verbs) > 1 || v.verbs[""] == nil {
+		value := req.PathValue(v.param)
+		if i := strings.LastIndexByte(value, ':'); i >= 0 {
+			if h, ok := v.verbs[value[i:]]; ok {
+				req.SetPathValue(v.param, value[:i])
+				h.ServeHTTP(w, req)
+				return
+			}
+		}
+	}
+	if h, ok := v.verbs[""]; ok {
+		h.ServeHTTP(w, req)
+		return
+	}
+	http.NotFound(w, req)
+}
+
+// pathValues 按 pathVar 从请求中取出路径参数
+func pathValues(vars []pathVar, value func(string) string) url.Values {
+	values := make(url.Values, len(vars))
+	for _, v := range vars {
+		var b strings.Builder
+		for _, p := range v.parts {
+			if strings.HasPrefix(p, "{") {
+				b.WriteString(value(p[1:]))
+			} else {
+				b.WriteString(p)
+			}
+		}
+		values[v.name] = []string{b.String()}
+	}
+	return values
+}
+
+type varsKey struct{}
+
+// Vars returns the route variables for the current request, if any.
+func Vars(req *http.Request) url.Values {
+	vars, _ := req.Context().Value(varsKey{}).([]pathVar)
+	return pathValues(vars, req.PathValue)
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseTemplate(t *testing.T) {
+	tests := []struct {
+		path    string
+		pattern string
+		verb    string
+		vars    []pathVar
+	}{
+		{
+			path:    "/v1/users/{id}",
+			pattern: "/v1/users/{id}",
+			vars:    []pathVar{{name: "id", parts: []string{"{id"}}},
+		},
+		{
+			path:    "/v1/users/{user.id}",
+			pattern: "/v1/users/{p0}",
+			vars:    []pathVar{{name: "user.id", parts: []string{"{p0"}}},
+		},
+		{
+			path:    "/v1/{name=shelves/*/books/*}",
+			pattern: "/v1/shelves/{p0}/books/{p1}",
+			vars:    []pathVar{{name: "name", parts: []string{"shelves", "/", "{p0", "/", "books", "/", "{p1"}}},
+		},
+		{
+			path:    "/v1/{name=files/**}",
+			pattern: "/v1/files/{p0...}",
+			vars:    []pathVar{{name: "name", parts: []string{"files", "/", "{p0"}}},
+		},
+		{
+			path:    "/v1/{name}:cancel",
+			pattern: "/v1/{name}",
+			verb:    ":cancel",
+			vars:    []pathVar{{name: "name", parts: []string{"{name"}}},
+		},
+		{
+			path:    "/v1/{name=operations/*}:cancel",
+			pattern: "/v1/operations/{p0}",
+			verb:    ":cancel",
+			vars:    []pathVar{{name: "name", parts: []string{"operations", "/", "{p0"}}},
+		},
+		{
+			path:    "/v1/books:batchGet",
+			pattern: "/v1/books:batchGet",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			tpl, verb := splitVerb(tt.path)
+			pattern, vars, err := parseTemplate(tpl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pattern != tt.pattern || verb != tt.verb {
+				t.Errorf("got (%q, %q), want (%q, %q)", pattern, verb, tt.pattern, tt.verb)
+			}
+			if !reflect.DeepEqual(vars, tt.vars) {
+				t.Errorf("got vars %v, want %v", vars, tt.vars)
+			}
+		})
+	}
+}
+
+func TestCustomVerb(t *testing.T) {
+	srv := NewServer()
+	for _, path := range []string{
+		"/v1/{name}",
+		"/v1/{name}:cancel",
+		"/v1/{name}:undelete",
+		"/v1/{name=operations/*}:cancel",
+	} {
+		srv.Handle(path, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			fmt.Fprintf(w, "%s %s", path, Vars(req).Get("name"))
+		}))
+	}
+	tests := []struct {
+		url  string
+		code int
+		body string
+	}{
+		{"/v1/foo", http.StatusOK, "/v1/{name} foo"},
+		{"/v1/foo:cancel", http.StatusOK, "/v1/{name}:cancel foo"},
+		{"/v1/foo:undelete", http.StatusOK, "/v1/{name}:undelete foo"},
+		{"/v1/operations/op1:cancel", http.StatusOK, "/v1/{name=operations/*}:cancel operations/op1"},
+		{"/v1/operations/op1", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+		body, _ := io.ReadAll(w.Body)
+		if w.Code != tt.code || (tt.body != "" && string(body) != tt.body) {
+			t.Errorf("%s: got %d %q, want %d %q", tt.url, w.Code, body, tt.code, tt.body)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+)
+
+// HandlerFunc defines a function to serve HTTP requests.
+type HandlerFunc func(Context) error
+
+// Router is an HTTP router.
+type Router struct {
+	prefix  string
+	pool    sync.Pool
+	srv     *Server
+	filters []FilterFunc
+}
+
+func newRouter(prefix string, srv *Server, filters ...FilterFunc) *Router {
+	r := &Router{
+		prefix:  prefix,
+		srv:     srv,
+		filters: filters,
+	}
+	r.pool.New = func() any {
+		return &wrapper{router: r}
+	}
+	return r
+}
+
+// Group returns a new router group.
+func (r *Router) Group(prefix string, filters ...FilterFunc) *Router {
+	var newFilters []FilterFunc
+	newFilters = append(newFilters, r.filters...)
+	newFilters = append(newFilters, filters...)
+	return newRouter(path.Join(r.prefix, prefix), r.srv, newFilters...)
+}
+
+// Handle registers a new route with a matcher for the URL path and method.
+// relativePath 支持标准库 ServeMux 的语法, 例如 /users/{id}, /files/{path...},
+// 也支持 google.api.http 的语法, 例如 /v1/{user.id}, /v1/{name=shelves/*}.
+func (r *Router) Handle(method, relativePath string, h HandlerFunc, filters ...FilterFunc) {
+	next := http.Handler(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		ctx := r.pool.Get().(*wrapper)
+		ctx.Reset(res, req)
+		if err := h(ctx); err != nil {
+			r.srv.ene(&ctx.w, req, err)
+		}
+		// 只设置了状态码没有写 body 时, 状态码在这里写出
+		ctx.w.flush()
+		ctx.Reset(nil, nil)
+		r.pool.Put(ctx)
+	}))
+	next = FilterChain(filters...)(next)
+	next = FilterChain(r.filters...)(next)
+	r.srv.handle(method, strings.TrimSuffix(r.prefix, "/")+relativePath, next)
+}
+
+// GET registers a new GET route for a path with matching handler in the router.
+func (r *Router) GET(path string, h HandlerFunc, m ...FilterFunc) {
+	r.Handle(http.MethodGet, path, h, m...)
+}
+
+// HEAD registers a new HEAD route for a path with matching handler in the router.
+func (r *Router) HEAD(path string, h HandlerFunc, m ...FilterFunc) {
+	r.Handle(http.MethodHead, path, h, m...)
+}
+
+// POST registers a new POST route for a path with matching handler in the router.
+func (r *Router) POST(path string, h HandlerFunc, m ...FilterFunc) {
+	r.Handle(http.MethodPost, path, h, m...)
+}
+
+// PUT registers a new PUT route for a path with matching handler in the router.
+func (r *Router) PUT(path string, h HandlerFunc, m ...FilterFunc) {
+	r.Handle(http.MethodPut, path, h, m...)
+}
+
+// PATCH registers a new PATCH route for a path with matching handler in the router.
+func (r *Router) PATCH(path string, h HandlerFunc, m ...FilterFunc) {
+	r.Handle(http.MethodPatch, path, h, m...)
+}
+
+// DELETE registers a new DELETE route for a path with matching handler in the router.
+func (r *Router) DELETE(path string, h HandlerFunc, m ...FilterFunc) {
+	r.Handle(http.MethodDelete, path, h, m...)
+}
+
+// CONNECT registers a new CONNECT route for a path with matching handler in the router.
+func (r *Router) CONNECT(path string, h HandlerFunc, m ...FilterFunc) {
+	r.Handle(http.MethodConnect, path, h, m...)
+}
+
+// OPTIONS registers a new OPTIONS route for a path with matching handler in the router.
+func (r *Router) OPTIONS(path string, h HandlerFunc, m ...FilterFunc) {
+	r.Handle(http.MethodOptions, path, h, m...)
+}
+
+// TRACE registers a new TRACE route for a path with matching handler in the router.
+func (r *Router) TRACE(path string, h HandlerFunc, m ...FilterFunc) {
+	r.Handle(http.MethodTrace, path, h, m...)
+}
@@ -0,0 +1,317 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"kratos_c/internal/endpoint"
+	"kratos_c/internal/host"
+	"kratos_c/internal/matcher"
+	"kratos_c/middleware"
+	"kratos_c/transport"
+)
+
+// SupportPackageIsVersion1 These constants should not be referenced from any other code.
+const SupportPackageIsVersion1 = true
+
+var (
+	_ transport.Server     = (*Server)(nil)
+	_ transport.Endpointer = (*Server)(nil)
+	_ http.Handler         = (*Server)(nil)
+)
+
+// ServerOption is an HTTP server option.
+type ServerOption func(*Server)
+
+// Network with server network.
+func Network(network string) ServerOption {
+	return func(s *Server) {
+		s.network = network
+	}
+}
+
+// Address with server address.
+func Address(addr string) ServerOption {
+	return func(s *Server) {
+		s.address = addr
+	}
+}
+
+// Endpoint with server address.
+func Endpoint(endpoint *url.URL) ServerOption {
+	return func(s *Server) {
+		s.endpoint = endpoint
+	}
+}
+
+// Timeout with server timeout.
+func Timeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.timeout = timeout
+	}
+}
+
+// Middleware with service middleware option.
+func Middleware(m ...middleware.Middleware) ServerOption {
+	return func(o *Server) {
+		o.middleware.Use(m...)
+	}
+}
+
+// OperationMiddleware 为匹配 selector 的 operation 追加中间件, 排在 Middleware 设置的默认中间件之后.
+// selector 的写法和 grpc.OperationMiddleware 相同, 例如 "/helloworld.Greeter/*".
+func OperationMiddleware(selector string, m ...middleware.Middleware) ServerOption {
+	return func(o *Server) {
+		o.middleware.Add(selector, m...)
+	}
+}
+
+// Filter with HTTP middleware option.
+func Filter(filters ...FilterFunc) ServerOption {
+	return func(o *Server) {
+		o.filters = filters
+	}
+}
+
+// RequestVarsDecoder with request decoder.
+func RequestVarsDecoder(dec DecodeRequestFunc) ServerOption {
+	return func(o *Server) {
+		o.decVars = dec
+	}
+}
+
+// RequestQueryDecoder with request decoder.
+func RequestQueryDecoder(dec DecodeRequestFunc) ServerOption {
+	return func(o *Server) {
+		o.decQuery = dec
+	}
+}
+
+// RequestDecoder with request decoder.
+func RequestDecoder(dec DecodeRequestFunc) ServerOption {
+	return func(o *Server) {
+		o.decBody = dec
+	}
+}
+
+// ResponseEncoder with response encoder.
+func ResponseEncoder(en EncodeResponseFunc) ServerOption {
+	return func(o *Server) {
+		o.enc = en
+	}
+}
+
+// ErrorEncoder with error encoder.
+func ErrorEncoder(en EncodeErrorFunc) ServerOption {
+	return func(o *Server) {
+		o.ene = en
+	}
+}
+
+// TLSConfig with TLS config.
+func TLSConfig(c *tls.Config) ServerOption {
+	return func(o *Server) {
+		o.tlsConf = c
+	}
+}
+
+// Listener with server lis
+func Listener(lis net.Listener) ServerOption {
+	return func(s *Server) {
+		s.lis = lis
+	}
+}
+
+// Server is an HTTP server wrapper.
+type Server struct {
+	*http.Server
+	lis        net.Listener
+	tlsConf    *tls.Config
+	endpoint   *url.URL
+	err        error
+	network    string
+	address    string
+	timeout    time.Duration
+	filters    []FilterFunc
+	middleware matcher.Matcher
+	decBody    DecodeRequestFunc
+	enc        EncodeResponseFunc
+	ene        EncodeErrorFunc
+	decVars    DecodeRequestFunc
+	decQuery   DecodeRequestFunc
+	router     *http.ServeMux
+	verbs      map[string]*verbMux
+}
+
+// NewServer creates an HTTP server by options.
+func NewServer(opts ...ServerOption) *Server {
+	srv := &Server{
+		network:    "tcp",
+		address:    ":0",
+		timeout:    1 * time.Second,
+		middleware: matcher.New(),
+		decVars:    DefaultRequestVars,
+		decQuery:   DefaultRequestQuery,
+		decBody:    DefaultRequestDecoder,
+		enc:        DefaultResponseEncoder,
+		ene:        DefaultErrorEncoder,
+	}
+	for _, o := range opts {
+		o(srv)
+	}
+	srv.router = http.NewServeMux()
+	srv.verbs = make(map[string]*verbMux)
+	srv.Server = &http.Server{
+		Handler:   FilterChain(srv.filters...)(srv.router),
+		TLSConfig: srv.tlsConf,
+	}
+	return srv
+}
+
+// Route registers an HTTP router.
+func (s *Server) Route(prefix string, filters ...FilterFunc) *Router {
+	return newRouter(prefix, s, filters...)
+}
+
+// Handle registers a new route with a matcher for the URL path.
+func (s *Server) Handle(path string, h http.Handler) {
+	s.handle("", path, h)
+}
+
+// HandlePrefix registers a new route with a matcher for the URL path prefix.
+func (s *Server) HandlePrefix(prefix string, h http.Handler) {
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	s.handle("", prefix, h)
+}
+
+// HandleFunc registers a new route with a matcher for the URL path.
+func (s *Server) HandleFunc(path string, h http.HandlerFunc) {
+	s.handle("", path, h)
+}
+
+// ServeHTTP should write reply headers and data to the ResponseWriter and then return.
+func (s *Server) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	s.Handler.ServeHTTP(res, req)
+}
+
+func (s *Server) handle(method, path string, h http.Handler) {
+	tpl, verb := splitVerb(path)
+	pattern, vars, err := parseTemplate(tpl)
+	if err != nil {
+		panic(err)
+	}
+	if method != "" {
+		pattern = method + " " + pattern
+	}
+	mux, ok := s.verbs[pattern]
+	if !ok {
+		mux = newVerbMux(pattern)
+		s.verbs[pattern] = mux
+		s.router.Handle(pattern, mux)
+	}
+	if _, ok := mux.verbs[verb]; ok {
+		panic("http: multiple registrations for " + path)
+	}
+	mux.verbs[verb] = s.filter(path, vars)(h)
+}
+
+// filter 在路由匹配之后为请求注入 Transport, 路径变量和超时
+func (s *Server) filter(pathTemplate string, vars []pathVar) FilterFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			var (
+				ctx    context.Context
+				cancel context.CancelFunc
+			)
+			if s.timeout > 0 {
+				ctx, cancel = context.WithTimeout(req.Context(), s.timeout)
+			} else {
+				ctx, cancel = context.WithCancel(req.Context())
+			}
+			defer cancel()
+			ctx = context.WithValue(ctx, varsKey{}, vars)
+
+			tr := &Transport{
+				operation:    pathTemplate,
+				pathTemplate: pathTemplate,
+				reqHeader:    headerCarrier(req.Header),
+				replyHeader:  headerCarrier(w.Header()),
+				request:      req,
+				response:     w,
+			}
+			if s.endpoint != nil {
+				tr.endpoint = s.endpoint.String()
+			}
+			tr.request = req.WithContext(transport.NewServerContext(ctx, tr))
+			next.ServeHTTP(w, tr.request)
+		})
+	}
+}
+
+// Endpoint return a real address to registry endpoint.
+// examples:
+//
+//	https://127.0.0.1:8000
+func (s *Server) Endpoint() (*url.URL, error) {
+	if err := s.listenAndEndpoint(); err != nil {
+		return nil, err
+	}
+	return s.endpoint, nil
+}
+
+// Start start the HTTP server.
+func (s *Server) Start(ctx context.Context) error {
+	if err := s.listenAndEndpoint(); err != nil {
+		return err
+	}
+	s.BaseContext = func(net.Listener) context.Context {
+		return ctx
+	}
+	var err error
+	if s.tlsConf != nil {
+		err = s.ServeTLS(s.lis, "", "")
+	} else {
+		err = s.Serve(s.lis)
+	}
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Stop stop the HTTP server, ctx 超时后强制关闭所有连接.
+func (s *Server) Stop(ctx context.Context) error {
+	err := s.Shutdown(ctx)
+	if err != nil && ctx.Err() != nil {
+		err = s.Close()
+	}
+	return err
+}
+
+func (s *Server) listenAndEndpoint() error {
+	if s.lis == nil {
+		lis, err := net.Listen(s.network, s.address)
+		if err != nil {
+			s.err = err
+			return err
+		}
+		s.lis = lis
+	}
+	if s.endpoint == nil {
+		addr, err := host.Extract(s.address, s.lis)
+		if err != nil {
+			s.err = err
+			return err
+		}
+		s.endpoint = endpoint.NewEndpoint(endpoint.Scheme("http", s.tlsConf != nil), addr)
+	}
+	return s.err
+}